@@ -0,0 +1,401 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+// Bookmark is a saved topic. Its topic page and article text are kept beside the
+// bookmark list so that it can be read offline.
+type Bookmark struct {
+	TopicID  string    `json:"topic_id"`
+	Title    string    `json:"title"`
+	TopicURL string    `json:"topic_url"`
+	Link     string    `json:"link,omitempty"` // External article URL
+	Domain   string    `json:"domain,omitempty"`
+	SavedAt  time.Time `json:"saved_at"`
+}
+
+// article returns the bookmark as a list article
+func (b Bookmark) article() Article {
+	return Article{Title: b.Title, Link: b.Link, CommentsLink: b.TopicURL, Domain: b.Domain}
+}
+
+// bookmarkStore is the list of bookmarks saved in a state file, with the offline copies
+// of their pages in a directory next to it
+type bookmarkStore struct {
+	Bookmarks []Bookmark `json:"bookmarks"`
+
+	path string // File the list is saved to; not saved if empty
+}
+
+// bookmarks is the bookmark store, set up by main. Without it bookmarks are not saved.
+var bookmarks *bookmarkStore
+
+// bookmarksPath returns the path of the bookmark list file
+func bookmarksPath() (string, error) {
+	return statePath("bookmarks.json")
+}
+
+// loadBookmarks reads the bookmark list saved at path
+func loadBookmarks(path string) (*bookmarkStore, error) {
+	store := &bookmarkStore{path: path}
+	if err := loadState(path, store); err != nil {
+		return nil, err
+	}
+	return store, nil
+}
+
+// find returns the bookmark of the topic
+func (b *bookmarkStore) find(topicID string) (Bookmark, bool) {
+	if b != nil {
+		for _, bookmark := range b.Bookmarks {
+			if bookmark.TopicID == topicID {
+				return bookmark, true
+			}
+		}
+	}
+	return Bookmark{}, false
+}
+
+// add saves a bookmark, replacing the bookmark of the same topic
+func (b *bookmarkStore) add(bookmark Bookmark) error {
+	if b == nil {
+		return errors.New("북마크 저장소가 없습니다")
+	}
+	if !isTopicID(bookmark.TopicID) {
+		return fmt.Errorf("잘못된 토픽 ID입니다: %q", bookmark.TopicID)
+	}
+	b.Bookmarks = append(b.without(bookmark.TopicID), bookmark)
+	return b.save()
+}
+
+// remove deletes the bookmark of the topic and its offline copy
+func (b *bookmarkStore) remove(topicID string) error {
+	if b == nil {
+		return nil
+	}
+	b.Bookmarks = b.without(topicID)
+	for _, ext := range []string{".html", ".txt"} {
+		if path := copyFile(b.copyDir(), topicID, ext); path != "" {
+			os.Remove(path)
+		}
+	}
+	return b.save()
+}
+
+func (b *bookmarkStore) without(topicID string) []Bookmark {
+	var kept []Bookmark
+	for _, bookmark := range b.Bookmarks {
+		if bookmark.TopicID != topicID {
+			kept = append(kept, bookmark)
+		}
+	}
+	return kept
+}
+
+func (b *bookmarkStore) save() error {
+	if b.path == "" {
+		return nil
+	}
+	return saveState(b.path, b)
+}
+
+// sorted returns the bookmarks by title, or newest first
+func (b *bookmarkStore) sorted(byTitle bool) []Bookmark {
+	if b == nil {
+		return nil
+	}
+	result := append([]Bookmark(nil), b.Bookmarks...)
+	sort.SliceStable(result, func(i, j int) bool {
+		if byTitle {
+			return strings.ToLower(result[i].Title) < strings.ToLower(result[j].Title)
+		}
+		return result[i].SavedAt.After(result[j].SavedAt)
+	})
+	return result
+}
+
+// copyDir returns the directory of the offline copies, or "" if they are not saved
+func (b *bookmarkStore) copyDir() string {
+	if b == nil || b.path == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Dir(b.path), "bookmarks")
+}
+
+// copyFile returns the path of an offline copy in dir, or "" if there is none. The topic
+// ID names the file, so anything but a number could point outside dir.
+func copyFile(dir, topicID, ext string) string {
+	if dir == "" || !isTopicID(topicID) {
+		return ""
+	}
+	return filepath.Join(dir, topicID+ext)
+}
+
+// saveCopy stores the topic page HTML and extracted article text of a bookmark.
+// Either may be empty when it could not be fetched.
+func (b *bookmarkStore) saveCopy(topicID, html, text string) error {
+	dir := b.copyDir()
+	if dir == "" {
+		return nil
+	}
+	if !isTopicID(topicID) {
+		return fmt.Errorf("잘못된 토픽 ID입니다: %q", topicID)
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for ext, data := range map[string]string{".html": html, ".txt": text} {
+		if data == "" {
+			continue
+		}
+		if err := os.WriteFile(copyFile(dir, topicID, ext), []byte(data), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// bookmarkCopy locates the offline copy of a bookmarked topic. Background work takes one
+// on the UI goroutine rather than reading the bookmark list itself.
+type bookmarkCopy struct {
+	topicID string
+	dir     string // Empty if the topic is not bookmarked or copies are not saved
+}
+
+// copyOf returns the offline copy of a topic
+func (b *bookmarkStore) copyOf(topicID string) bookmarkCopy {
+	if _, ok := b.find(topicID); !ok {
+		return bookmarkCopy{topicID: topicID}
+	}
+	return bookmarkCopy{topicID: topicID, dir: b.copyDir()}
+}
+
+// page returns the saved topic page of a bookmark
+func (b *bookmarkStore) page(topicID string) (*TopicPage, error) {
+	return b.copyOf(topicID).page()
+}
+
+// text returns the saved article text of a bookmark
+func (b *bookmarkStore) text(topicID string) (string, bool) {
+	return b.copyOf(topicID).text()
+}
+
+func (c bookmarkCopy) page() (*TopicPage, error) {
+	path := copyFile(c.dir, c.topicID, ".html")
+	if path == "" {
+		return nil, os.ErrNotExist
+	}
+	html, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTopicPage(c.topicID, string(html))
+}
+
+func (c bookmarkCopy) text() (string, bool) {
+	path := copyFile(c.dir, c.topicID, ".txt")
+	if path == "" {
+		return "", false
+	}
+	text, err := os.ReadFile(path)
+	return string(text), err == nil && len(text) > 0
+}
+
+// hasCopy reports whether the topic page of a bookmark was saved
+func (b *bookmarkStore) hasCopy(topicID string) bool {
+	path := copyFile(b.copyDir(), topicID, ".html")
+	if path == "" {
+		return false
+	}
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// fetchBookmarkCopy fetches the topic page of a bookmark and the text of its article,
+// filling in the external link and domain from the page when they are missing
+func fetchBookmarkCopy(bookmark Bookmark) (Bookmark, string, string, error) {
+	html, err := fetchCached(bookmark.TopicURL)
+	if err != nil {
+		return bookmark, "", "", err
+	}
+	if bookmark.Link == "" {
+		if link, _, _, err := parseGeekNewsTopicLink(html); err == nil {
+			bookmark.Link = link
+		}
+	}
+	if bookmark.Domain == "" && bookmark.Link != "" {
+		bookmark.Domain = extractDomainFromURL(bookmark.Link)
+	}
+
+	text := ""
+	if strings.HasPrefix(bookmark.Link, "http") {
+		text, _ = extractArticleText(bookmark.Link) // The topic page alone is still worth keeping
+	}
+	return bookmark, html, text, nil
+}
+
+// toggleBookmark bookmarks the current topic, or removes its bookmark. The offline copy
+// is fetched in the background.
+func (s *session) toggleBookmark() {
+	article, ok := s.selectedArticle()
+	topicID := extractTopicID(article.CommentsLink)
+	if !ok || !isTopicID(topicID) {
+		return
+	}
+
+	if _, saved := bookmarks.find(topicID); saved {
+		if err := bookmarks.remove(topicID); err != nil {
+			s.setStatus(paint(theme.Error, "북마크를 삭제하지 못했습니다: "+tview.Escape(err.Error())))
+			return
+		}
+		s.refreshSaved()
+		s.setStatus("북마크를 삭제했습니다")
+		return
+	}
+
+	bookmark := Bookmark{
+		TopicID:  topicID,
+		Title:    article.Title,
+		TopicURL: article.CommentsLink,
+		Link:     article.Link,
+		Domain:   article.Domain,
+		SavedAt:  time.Now(),
+	}
+	if bookmark.Link == "" && s.page != nil && s.page.ID == topicID && s.page.Content != nil {
+		bookmark.Link = s.page.Content.ExternalLink
+	}
+	if err := bookmarks.add(bookmark); err != nil {
+		s.setStatus(paint(theme.Error, "북마크를 저장하지 못했습니다: "+tview.Escape(err.Error())))
+		return
+	}
+	s.setStatus("북마크했습니다 (오프라인 사본 저장 중...)")
+
+	go func() {
+		updated, html, text, err := fetchBookmarkCopy(bookmark)
+		s.app.QueueUpdateDraw(func() {
+			if _, ok := bookmarks.find(topicID); !ok {
+				return // Removed in the meantime
+			}
+			if err == nil {
+				err = bookmarks.saveCopy(topicID, html, text)
+			}
+			if err == nil {
+				err = bookmarks.add(updated)
+			}
+			if err != nil {
+				s.setStatus(paint(theme.Error, "오프라인 사본을 저장하지 못했습니다: "+tview.Escape(err.Error())))
+				return
+			}
+			s.refreshSaved()
+			s.setStatus("북마크했습니다")
+		})
+	}()
+}
+
+// savedView lists the bookmarks
+type savedView struct {
+	list    *tview.List
+	items   []Bookmark // Bookmark of each list item
+	byTitle bool       // Sort by title instead of newest first
+}
+
+// openSaved shows the bookmark list
+func (s *session) openSaved() {
+	if s.saved == nil {
+		s.saved = &savedView{list: styleList(tview.NewList().ShowSecondaryText(true))}
+		s.listMouse(s.saved.list)
+	}
+	s.saved.fill()
+	s.showPage("saved", s.saved.list)
+}
+
+// refreshSaved updates the bookmark list, if it was opened, keeping the selection in place
+func (s *session) refreshSaved() {
+	if s.saved == nil {
+		return
+	}
+	current := s.saved.list.GetCurrentItem()
+	s.saved.fill()
+	s.saved.list.SetCurrentItem(min(current, len(s.saved.items)-1))
+}
+
+// toggleSavedSort sorts the bookmark list by title or by saved time
+func (s *session) toggleSavedSort() {
+	s.saved.byTitle = !s.saved.byTitle
+	s.saved.fill()
+	if s.saved.byTitle {
+		s.setStatus("제목순 정렬")
+	} else {
+		s.setStatus("저장한 시간순 정렬")
+	}
+}
+
+// fill lists the bookmarks in the chosen order
+func (v *savedView) fill() {
+	v.items = bookmarks.sorted(v.byTitle)
+	v.list.Clear()
+	for _, bookmark := range v.items {
+		secondary := bookmark.SavedAt.Local().Format("2006-01-02 15:04")
+		if bookmark.Domain != "" {
+			secondary = bookmark.Domain + " · " + secondary
+		}
+		if !bookmarks.hasCopy(bookmark.TopicID) {
+			secondary += " · 오프라인 사본 없음"
+		}
+		v.list.AddItem(tview.Escape(bookmark.Title), secondary, 0, nil)
+	}
+	if len(v.items) == 0 {
+		v.list.AddItem("저장한 글이 없습니다", "'b' 키로 글을 북마크하세요", 0, nil)
+	}
+}
+
+// selectedTopic returns the topic of the highlighted bookmark
+func (v *savedView) selectedTopic() (Article, bool) {
+	if v == nil {
+		return Article{}, false
+	}
+	index := v.list.GetCurrentItem()
+	if index < 0 || index >= len(v.items) {
+		return Article{}, false
+	}
+	return v.items[index].article(), true
+}
+
+// savedTopicPage returns the offline copy of a bookmarked topic page, for when fetching it failed
+func savedTopicPage(topicID string, fetchErr error) (*TopicPage, error) {
+	page, err := bookmarks.page(topicID)
+	if err != nil {
+		return nil, fetchErr
+	}
+	return page, nil
+}
+
+// showSavedArticle shows the offline copy of the article of a bookmarked topic, if there is one
+func (s *session) showSavedArticle(article Article) bool {
+	text, ok := bookmarks.text(extractTopicID(article.CommentsLink))
+	if !ok {
+		return false
+	}
+	s.showText("article", func(width int) ([]string, []int) {
+		return layoutText(text, width)
+	})
+	s.markRead(article)
+	s.recordView("article", article)
+	s.setStatus(fmt.Sprintf("오프라인: %s에 저장한 사본입니다", savedTime(article)))
+	return true
+}
+
+// savedTime returns when the topic of article was bookmarked
+func savedTime(article Article) string {
+	bookmark, _ := bookmarks.find(extractTopicID(article.CommentsLink))
+	return bookmark.SavedAt.Local().Format("2006-01-02 15:04")
+}
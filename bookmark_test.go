@@ -0,0 +1,124 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestBookmarkStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bookmarks.json")
+	store, err := loadBookmarks(path)
+	if err != nil {
+		t.Fatalf("Unexpected error for a missing list: %v", err)
+	}
+
+	now := time.Now()
+	for _, b := range []Bookmark{
+		{TopicID: "1", Title: "나중 글", TopicURL: geekNewsBaseURL + "topic?id=1", SavedAt: now.Add(-time.Hour)},
+		{TopicID: "2", Title: "가장 최근", TopicURL: geekNewsBaseURL + "topic?id=2", SavedAt: now},
+		{TopicID: "3", Title: "Go 릴리스", TopicURL: geekNewsBaseURL + "topic?id=3", SavedAt: now.Add(-2 * time.Hour)},
+	} {
+		if err := store.add(b); err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+	}
+	// Bookmarking a topic again replaces its bookmark
+	if err := store.add(Bookmark{TopicID: "3", Title: "Go 릴리스", Domain: "go.dev", SavedAt: now.Add(-2 * time.Hour)}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	store, err = loadBookmarks(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(store.Bookmarks) != 3 {
+		t.Fatalf("Expected 3 bookmarks, got %+v", store.Bookmarks)
+	}
+	if b, ok := store.find("3"); !ok || b.Domain != "go.dev" {
+		t.Errorf("Expected the replaced bookmark, got %+v", b)
+	}
+
+	byTime := store.sorted(false)
+	if byTime[0].TopicID != "2" || byTime[1].TopicID != "1" || byTime[2].TopicID != "3" {
+		t.Errorf("Expected newest first, got %+v", byTime)
+	}
+	byTitle := store.sorted(true)
+	if byTitle[0].TopicID != "3" || byTitle[1].TopicID != "2" || byTitle[2].TopicID != "1" {
+		t.Errorf("Expected title order, got %+v", byTitle)
+	}
+
+	if err := store.remove("2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, ok := store.find("2"); ok {
+		t.Error("Expected the bookmark to be removed")
+	}
+}
+
+func TestBookmarkOfflineCopy(t *testing.T) {
+	html, err := os.ReadFile("testdata/geeknews_topic_full.html")
+	if err != nil {
+		t.Fatalf("Failed to read test file: %v", err)
+	}
+
+	store, _ := loadBookmarks(filepath.Join(t.TempDir(), "bookmarks.json"))
+	if err := store.add(Bookmark{TopicID: "7", Title: "저장한 글", SavedAt: time.Now()}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if store.hasCopy("7") {
+		t.Error("Expected no offline copy before saving one")
+	}
+	if err := store.saveCopy("7", string(html), "추출한 기사 본문"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	page, err := store.page("7")
+	if err != nil {
+		t.Fatalf("Unexpected error reading the saved page: %v", err)
+	}
+	if page.ID != "7" || page.Content == nil || len(page.Comments) == 0 {
+		t.Errorf("Expected the saved topic and comments, got %+v", page)
+	}
+	if text, ok := store.text("7"); !ok || text != "추출한 기사 본문" {
+		t.Errorf("Expected the saved article text, got %q", text)
+	}
+
+	if err := store.remove("7"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(store.copyDir(), "7.html")); !os.IsNotExist(err) {
+		t.Errorf("Expected the offline copy to be deleted, got %v", err)
+	}
+	if _, ok := store.text("7"); ok {
+		t.Error("Expected no article text after removing the bookmark")
+	}
+}
+
+func TestBookmarkRejectsUnsafeTopicID(t *testing.T) {
+	root := t.TempDir()
+	store, _ := loadBookmarks(filepath.Join(root, "state", "bookmarks.json"))
+	victim := filepath.Join(root, "victim.html")
+	os.WriteFile(victim, []byte("keep"), 0o644)
+
+	// A link like topic?id=../../victim must not reach outside the copies directory
+	for _, id := range []string{"../../victim", "../victim", "1/../2", "-1", ""} {
+		if err := store.add(Bookmark{TopicID: id}); err == nil {
+			t.Errorf("%q: expected the bookmark to be rejected", id)
+		}
+		if err := store.saveCopy(id, "<html>", "text"); err == nil {
+			t.Errorf("%q: expected the copy to be rejected", id)
+		}
+		store.remove(id)
+		if _, err := store.copyOf(id).page(); err == nil {
+			t.Errorf("%q: expected no saved page", id)
+		}
+	}
+	if data, err := os.ReadFile(victim); err != nil || string(data) != "keep" {
+		t.Errorf("Expected the file outside the bookmarks to be untouched, got %q (%v)", data, err)
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 2 {
+		t.Errorf("Expected nothing written outside the state directory, got %d entries", len(entries))
+	}
+}
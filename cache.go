@@ -0,0 +1,224 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// maxMemoryEntries is the number of pages kept in memory before the least recently used is evicted
+const maxMemoryEntries = 200
+
+// CacheEntry is a fetched page, in memory or on disk
+type CacheEntry struct {
+	Key       string    `json:"key"` // URL
+	Data      string    `json:"data"`
+	Timestamp time.Time `json:"timestamp"`
+
+	used time.Time // Last read, for LRU eviction
+}
+
+// pageCache caches fetched pages by URL in memory and on disk, with a TTL for each
+type pageCache struct {
+	mu        sync.Mutex
+	memory    map[string]*CacheEntry
+	dir       string // Disk cache directory; no disk cache if empty
+	memoryTTL time.Duration
+	diskTTL   time.Duration
+}
+
+// cache is the page cache used by fetchCached, set up by main. Without it pages are not cached.
+var cache *pageCache
+
+func newPageCache(dir string, memoryTTL, diskTTL time.Duration) *pageCache {
+	return &pageCache{
+		memory:    make(map[string]*CacheEntry),
+		dir:       dir,
+		memoryTTL: memoryTTL,
+		diskTTL:   diskTTL,
+	}
+}
+
+// pageCacheDir returns the directory of the disk page cache
+func pageCacheDir() (string, error) {
+	dir, err := cacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "pages"), nil
+}
+
+// fetchCached returns the page at url from the cache, fetching and caching it if needed
+func fetchCached(url string) (string, error) {
+	data, _, err := fetchCachedTime(url)
+	return data, err
+}
+
+// fetchCachedTime is fetchCached also returning when the page was fetched
+func fetchCachedTime(url string) (string, time.Time, error) {
+	return fetchCachedWith(url, fetchWebpage)
+}
+
+// fetchCachedWith is fetchCachedTime fetching the pages missing from the cache with fetch
+func fetchCachedWith(url string, fetch func(string) (string, error)) (string, time.Time, error) {
+	if cache == nil {
+		data, err := fetch(url)
+		return data, time.Now(), err
+	}
+	return cache.getTime(url, fetch)
+}
+
+// get returns the cached page for url, or fetches it with fetch and caches it
+func (c *pageCache) get(url string, fetch func(string) (string, error)) (string, error) {
+	data, _, err := c.getTime(url, fetch)
+	return data, err
+}
+
+// getTime is get also returning when the page was fetched
+func (c *pageCache) getTime(url string, fetch func(string) (string, error)) (string, time.Time, error) {
+	if entry, ok := c.lookup(url); ok {
+		return entry.Data, entry.Timestamp, nil
+	}
+
+	data, err := fetch(url)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return data, c.put(url, data), nil
+}
+
+func (c *pageCache) lookup(url string) (*CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := time.Now()
+	if entry, ok := c.memory[url]; ok {
+		if now.Sub(entry.Timestamp) < c.memoryTTL {
+			entry.used = now
+			return entry, true
+		}
+		delete(c.memory, url)
+	}
+
+	entry, err := c.readDisk(url)
+	if err != nil || now.Sub(entry.Timestamp) >= c.diskTTL {
+		return nil, false
+	}
+	if now.Sub(entry.Timestamp) < c.memoryTTL {
+		c.remember(entry)
+	}
+	return entry, true
+}
+
+// put caches the page at url and returns its timestamp
+func (c *pageCache) put(url, data string) time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := &CacheEntry{Key: url, Data: data, Timestamp: time.Now()}
+	if c.memoryTTL > 0 {
+		c.remember(entry)
+	}
+	if c.dir != "" && c.diskTTL > 0 {
+		c.writeDisk(entry)
+	}
+	return entry.Timestamp
+}
+
+// remember adds entry to the memory cache, evicting the least recently used entry when full
+func (c *pageCache) remember(entry *CacheEntry) {
+	entry.used = time.Now()
+	c.memory[entry.Key] = entry
+	if len(c.memory) <= maxMemoryEntries {
+		return
+	}
+
+	var oldest *CacheEntry
+	for _, e := range c.memory {
+		if oldest == nil || e.used.Before(oldest.used) {
+			oldest = e
+		}
+	}
+	delete(c.memory, oldest.Key)
+}
+
+// invalidate drops every cached page, in memory and on disk
+func (c *pageCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.memory = make(map[string]*CacheEntry)
+	if c.dir != "" {
+		os.RemoveAll(c.dir)
+	}
+}
+
+// cleanup removes expired pages from the disk cache
+func (c *pageCache) cleanup() {
+	if c.dir == "" {
+		return
+	}
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		path := filepath.Join(c.dir, e.Name())
+		entry, err := readCacheEntry(path)
+		if err != nil || time.Since(entry.Timestamp) >= c.diskTTL {
+			os.Remove(path)
+		}
+	}
+}
+
+func (c *pageCache) diskPath(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:16])+".json")
+}
+
+func (c *pageCache) readDisk(url string) (*CacheEntry, error) {
+	if c.dir == "" {
+		return nil, os.ErrNotExist
+	}
+	entry, err := readCacheEntry(c.diskPath(url))
+	if err != nil {
+		return nil, err
+	}
+	if entry.Key != url {
+		return nil, os.ErrNotExist
+	}
+	return entry, nil
+}
+
+func readCacheEntry(path string) (*CacheEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// writeDisk stores entry on disk. The cache is best effort: errors are ignored.
+func (c *pageCache) writeDisk(entry *CacheEntry) {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	// Write to a temporary file first so that readers never see a partial entry
+	tmp := c.diskPath(entry.Key) + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return
+	}
+	os.Rename(tmp, c.diskPath(entry.Key))
+}
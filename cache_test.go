@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// countingFetch returns a fetch function serving "page N" and counting its calls
+func countingFetch(calls *int) func(string) (string, error) {
+	return func(url string) (string, error) {
+		*calls++
+		return fmt.Sprintf("page %d", *calls), nil
+	}
+}
+
+func TestPageCacheMemory(t *testing.T) {
+	c := newPageCache("", time.Hour, 0)
+	calls := 0
+	fetch := countingFetch(&calls)
+
+	for i := 0; i < 3; i++ {
+		data, err := c.get("https://example.com/a", fetch)
+		if err != nil || data != "page 1" {
+			t.Fatalf("Expected cached page 1, got %q, %v", data, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Expected 1 fetch, got %d", calls)
+	}
+
+	c.invalidate()
+	if data, _ := c.get("https://example.com/a", fetch); data != "page 2" {
+		t.Errorf("Expected a fresh fetch after invalidate, got %q", data)
+	}
+}
+
+func TestPageCacheDisk(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "pages")
+	calls := 0
+	fetch := countingFetch(&calls)
+
+	if _, err := newPageCache(dir, time.Hour, time.Hour).get("https://example.com/a", fetch); err != nil {
+		t.Fatal(err)
+	}
+
+	// A new cache (next run) finds the page on disk
+	data, err := newPageCache(dir, time.Hour, time.Hour).get("https://example.com/a", fetch)
+	if err != nil || data != "page 1" || calls != 1 {
+		t.Errorf("Expected page 1 from disk without fetching, got %q, %v after %d fetches", data, err, calls)
+	}
+}
+
+func TestPageCacheExpiry(t *testing.T) {
+	dir := t.TempDir()
+	c := newPageCache(dir, time.Hour, time.Hour)
+	c.writeDisk(&CacheEntry{Key: "https://example.com/old", Data: "old", Timestamp: time.Now().Add(-2 * time.Hour)})
+	c.writeDisk(&CacheEntry{Key: "https://example.com/new", Data: "new", Timestamp: time.Now()})
+
+	calls := 0
+	if data, _ := c.get("https://example.com/old", countingFetch(&calls)); data != "page 1" {
+		t.Errorf("Expected expired entry to be fetched again, got %q", data)
+	}
+
+	c.writeDisk(&CacheEntry{Key: "https://example.com/old", Data: "old", Timestamp: time.Now().Add(-2 * time.Hour)})
+	c.cleanup()
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("Expected cleanup to leave only the fresh entry, got %d entries", len(entries))
+	}
+}
+
+func TestPageCacheEviction(t *testing.T) {
+	c := newPageCache("", time.Hour, 0)
+	calls := 0
+	fetch := countingFetch(&calls)
+	for i := 0; i <= maxMemoryEntries; i++ {
+		c.get(fmt.Sprintf("https://example.com/%d", i), fetch)
+		time.Sleep(time.Microsecond) // Distinct use times
+	}
+
+	if len(c.memory) != maxMemoryEntries {
+		t.Errorf("Expected %d entries, got %d", maxMemoryEntries, len(c.memory))
+	}
+	if _, ok := c.memory["https://example.com/0"]; ok {
+		t.Error("Expected the least recently used entry to be evicted")
+	}
+}
+
+func TestPageCacheErrorsNotCached(t *testing.T) {
+	c := newPageCache("", time.Hour, 0)
+	failing := func(string) (string, error) { return "", errors.New("offline") }
+	if _, err := c.get("https://example.com/a", failing); err == nil {
+		t.Fatal("Expected fetch error")
+	}
+
+	calls := 0
+	if data, _ := c.get("https://example.com/a", countingFetch(&calls)); data != "page 1" {
+		t.Errorf("Expected failed fetch not to be cached, got %q", data)
+	}
+}
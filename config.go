@@ -0,0 +1,328 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Config is the user configuration. Values come from, in increasing priority:
+// built-in defaults, ~/.config/gn-text/config.yaml, GN_TEXT_* environment
+// variables and command line flags.
+type Config struct {
+	WrapWidth         int `yaml:"wrap_width"`          // Maximum text width; 0 fills the view
+	FallbackWidth     int `yaml:"fallback_width"`      // Terminal width assumed when it cannot be measured
+	CacheTTLMemory    int `yaml:"cache_ttl_memory"`    // Seconds
+	CacheTTLDisk      int `yaml:"cache_ttl_disk"`      // Seconds
+	CommentDepthLimit int `yaml:"comment_depth_limit"` // Deeper replies are folded
+	RequestTimeout    int `yaml:"request_timeout"`     // Seconds per HTTP request
+	RetryCount        int `yaml:"retry_count"`         // Retries after a failed HTTP request
+	SplitMinWidth     int `yaml:"split_min_width"`     // Narrowest terminal showing the preview pane; 0 never shows it
+
+	ExportDir      string `yaml:"export_dir"`      // Directory Markdown exports are written to; empty is the current directory
+	ExportFilename string `yaml:"export_filename"` // File name of exports, with {id}, {title}, {author} and {date} placeholders
+
+	Theme  string           `yaml:"theme"`  // Name of a built-in or custom theme
+	Themes map[string]Theme `yaml:"themes"` // Custom themes by name
+
+	Keys map[string]keyList `yaml:"keys"` // Action name → keys replacing its default keys
+
+	Watch  WatchConfig  `yaml:"watch"`  // Rules of `gn-text watch`
+	Digest DigestConfig `yaml:"digest"` // Defaults and mail server of `gn-text digest`
+
+	Path    string            `yaml:"-"` // Config file location
+	Found   bool              `yaml:"-"` // Whether the config file exists
+	Sources map[string]string `yaml:"-"` // Setting key → where its value came from
+}
+
+// settings is the effective configuration, set up by main before anything runs
+var settings = defaultConfig()
+
+func defaultConfig() *Config {
+	return &Config{
+		WrapWidth:         0,
+		FallbackWidth:     120,
+		CacheTTLMemory:    600,
+		CacheTTLDisk:      3600,
+		CommentDepthLimit: 10,
+		RequestTimeout:    10,
+		RetryCount:        2,
+		SplitMinWidth:     100,
+		ExportFilename:    "{date}-{id}-{title}.md",
+		Theme:             "dark",
+		Watch:             WatchConfig{Interval: 300},
+		Digest:            DigestConfig{Limit: 10, Hours: 24, Format: "markdown", SMTP: SMTPConfig{Port: 587, Subject: "GeekNews 다이제스트 {date}"}},
+		Sources:           make(map[string]string),
+	}
+}
+
+// setting is one scalar config value, settable from the file, the environment and a flag
+type setting struct {
+	Key   string // Key in the config file
+	Usage string
+	Min   int // Smallest allowed value of an int setting
+	Value any // *int or *string
+}
+
+func (c *Config) settings() []setting {
+	return []setting{
+		{"wrap_width", "Maximum text width (0 = fill the view)", 0, &c.WrapWidth},
+		{"fallback_width", "Terminal width when it cannot be measured", 1, &c.FallbackWidth},
+		{"cache_ttl_memory", "Memory cache TTL in seconds", 0, &c.CacheTTLMemory},
+		{"cache_ttl_disk", "Disk cache TTL in seconds", 0, &c.CacheTTLDisk},
+		{"comment_depth_limit", "Deepest comment level shown unfolded", 0, &c.CommentDepthLimit},
+		{"request_timeout", "HTTP request timeout in seconds", 1, &c.RequestTimeout},
+		{"retry_count", "Retries after a failed HTTP request", 0, &c.RetryCount},
+		{"split_min_width", "Narrowest terminal showing the preview pane (0 = never)", 0, &c.SplitMinWidth},
+		{"export_dir", "Directory Markdown exports are written to (empty = current directory)", 0, &c.ExportDir},
+		{"export_filename", "File name of Markdown exports ({id}, {title}, {author}, {date})", 0, &c.ExportFilename},
+		{"theme", "Color theme: dark, light, high-contrast or a custom theme", 0, &c.Theme},
+	}
+}
+
+// envName returns the environment variable overriding the setting, e.g. GN_TEXT_WRAP_WIDTH
+func (s setting) envName() string {
+	return "GN_TEXT_" + strings.ToUpper(s.Key)
+}
+
+// flagName returns the command line flag overriding the setting, e.g. wrap-width
+func (s setting) flagName() string {
+	return strings.ReplaceAll(s.Key, "_", "-")
+}
+
+// keyList is one key (`open: l`) or a list of keys (`open: [l, Enter]`) in the config file
+type keyList []string
+
+func (k *keyList) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		*k = keyList{node.Value}
+		return nil
+	}
+	var keys []string
+	if err := node.Decode(&keys); err != nil {
+		return err
+	}
+	*k = keys
+	return nil
+}
+
+// configPath returns the config file location: $XDG_CONFIG_HOME/gn-text/config.yaml,
+// falling back to ~/.config/gn-text/config.yaml
+func configPath() (string, error) {
+	base := os.Getenv("XDG_CONFIG_HOME")
+	if base == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		base = filepath.Join(home, ".config")
+	}
+	return filepath.Join(base, "gn-text", "config.yaml"), nil
+}
+
+// loadConfig reads the config file at path on top of the defaults. A missing file is not an error.
+func loadConfig(path string) (*Config, error) {
+	cfg := defaultConfig()
+	cfg.Path = path
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cfg.Found = true
+
+	if err := yaml.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	// Record which keys the file sets
+	var present map[string]yaml.Node
+	if err := yaml.Unmarshal(data, &present); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	for key := range present {
+		cfg.Sources[key] = "file"
+	}
+
+	return cfg, nil
+}
+
+// applyEnv overrides settings from GN_TEXT_* environment variables
+func (c *Config) applyEnv() error {
+	for _, s := range c.settings() {
+		value, ok := os.LookupEnv(s.envName())
+		if !ok {
+			continue
+		}
+		switch v := s.Value.(type) {
+		case *int:
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil {
+				return fmt.Errorf("%s: %q is not a number", s.envName(), value)
+			}
+			*v = n
+		case *string:
+			*v = strings.TrimSpace(value)
+		}
+		c.Sources[s.Key] = "env " + s.envName()
+	}
+	return nil
+}
+
+// configFlags holds the command line flags overriding settings
+type configFlags struct {
+	fs     *flag.FlagSet
+	values map[string]any // Flag name → *int or *string
+}
+
+// registerConfigFlags adds a flag for every setting to fs
+func registerConfigFlags(fs *flag.FlagSet) *configFlags {
+	cf := &configFlags{fs: fs, values: make(map[string]any)}
+	defaults := defaultConfig()
+	for _, s := range defaults.settings() {
+		switch v := s.Value.(type) {
+		case *int:
+			cf.values[s.flagName()] = fs.Int(s.flagName(), *v, s.Usage)
+		case *string:
+			cf.values[s.flagName()] = fs.String(s.flagName(), *v, s.Usage)
+		}
+	}
+	return cf
+}
+
+// apply overrides settings with the flags given on the command line
+func (cf *configFlags) apply(c *Config) {
+	set := make(map[string]bool)
+	cf.fs.Visit(func(f *flag.Flag) { set[f.Name] = true })
+
+	for _, s := range c.settings() {
+		if set[s.flagName()] {
+			switch v := s.Value.(type) {
+			case *int:
+				*v = *cf.values[s.flagName()].(*int)
+			case *string:
+				*v = *cf.values[s.flagName()].(*string)
+			}
+			c.Sources[s.Key] = "flag --" + s.flagName()
+		}
+	}
+}
+
+// validate checks that every setting is in range and the theme exists
+func (c *Config) validate() error {
+	for _, s := range c.settings() {
+		if v, ok := s.Value.(*int); ok && *v < s.Min {
+			return fmt.Errorf("%s must be at least %d (from %s), got %d", s.Key, s.Min, c.source(s.Key), *v)
+		}
+	}
+	if _, err := exportFilename(c.ExportFilename, &TopicContent{}, "1", time.Now()); err != nil {
+		return fmt.Errorf("%w (from %s)", err, c.source("export_filename"))
+	}
+	if _, err := c.theme(); err != nil {
+		return fmt.Errorf("%w (from %s)", err, c.source("theme"))
+	}
+	if c.Watch.Interval < 1 {
+		return fmt.Errorf("watch.interval must be at least 1 (from %s), got %d", c.source("watch"), c.Watch.Interval)
+	}
+	if err := c.Watch.compile(); err != nil {
+		return fmt.Errorf("%w (from %s)", err, c.source("watch"))
+	}
+	if err := c.Digest.validate(); err != nil {
+		return fmt.Errorf("%w (from %s)", err, c.source("digest"))
+	}
+	return nil
+}
+
+func (c *Config) source(key string) string {
+	if source, ok := c.Sources[key]; ok {
+		return source
+	}
+	return "default"
+}
+
+// writeEffective writes the merged configuration as YAML, commenting where each value came from
+func (c *Config) writeEffective(w io.Writer) {
+	status := "not found"
+	if c.Found {
+		status = "found"
+	}
+	fmt.Fprintf(w, "# config file: %s (%s)\n", c.Path, status)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 1, ' ', 0)
+	for _, s := range c.settings() {
+		switch v := s.Value.(type) {
+		case *int:
+			fmt.Fprintf(tw, "%s: %d\t# %s\n", s.Key, *v, c.source(s.Key))
+		case *string:
+			value, _ := yaml.Marshal(*v) // Quoted where YAML needs it ("", "{id}.md")
+			fmt.Fprintf(tw, "%s: %s\t# %s\n", s.Key, strings.TrimSpace(string(value)), c.source(s.Key))
+		}
+	}
+	tw.Flush()
+
+	if len(c.Themes) > 0 {
+		fmt.Fprintf(w, "themes: # %s\n", c.source("themes"))
+		data, _ := yaml.Marshal(c.Themes)
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+
+	if len(c.Watch.Rules) > 0 || c.Watch.Command != "" {
+		fmt.Fprintf(w, "watch: # %s\n", c.source("watch"))
+		data, _ := yaml.Marshal(c.Watch)
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+
+	if _, ok := c.Sources["digest"]; ok {
+		fmt.Fprintf(w, "digest: # %s\n", c.source("digest"))
+		data, _ := yaml.Marshal(c.Digest)
+		for _, line := range strings.Split(strings.TrimRight(string(data), "\n"), "\n") {
+			fmt.Fprintln(w, "  "+line)
+		}
+	}
+
+	if len(c.Keys) == 0 {
+		fmt.Fprintln(w, "keys: {} # default (see `gn-text keys`)")
+		return
+	}
+	fmt.Fprintf(w, "keys: # %s (see `gn-text keys`)\n", c.source("keys"))
+	names := make([]string, 0, len(c.Keys))
+	for name := range c.Keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		quoted := make([]string, len(c.Keys[name]))
+		for i, key := range c.Keys[name] {
+			quoted[i] = strconv.Quote(key)
+		}
+		fmt.Fprintf(w, "  %s: [%s]\n", name, strings.Join(quoted, ", "))
+	}
+}
+
+// runConfig implements `gn-text config`, which prints the effective configuration
+func runConfig(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("config", flag.ContinueOnError)
+	fs.SetOutput(w)
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	settings.writeEffective(w)
+	return 0
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadConfigKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "keys:\n  down: [j, C-n]\n  top: gg\n  author: []\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if strings.Join(cfg.Keys["down"], ",") != "j,C-n" {
+		t.Errorf("Expected list of keys, got %v", cfg.Keys["down"])
+	}
+	if strings.Join(cfg.Keys["top"], ",") != "gg" {
+		t.Errorf("Expected single key, got %v", cfg.Keys["top"])
+	}
+	if keys, ok := cfg.Keys["author"]; !ok || len(keys) != 0 {
+		t.Errorf("Expected empty key list, got %v (present: %v)", keys, ok)
+	}
+}
+
+func TestLoadConfigMissingFile(t *testing.T) {
+	cfg, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("Expected missing config to be empty, got error: %v", err)
+	}
+	if len(cfg.Keys) != 0 {
+		t.Errorf("Expected no keys, got %v", cfg.Keys)
+	}
+}
+
+func TestLoadConfigInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("keys: [not, a, map]\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := loadConfig(path); err == nil {
+		t.Error("Expected error for invalid config")
+	}
+}
+
+func TestConfigPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "wrap_width: 80\nretry_count: 5\ncomment_depth_limit: 4\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("GN_TEXT_RETRY_COUNT", "1")
+	t.Setenv("GN_TEXT_COMMENT_DEPTH_LIMIT", "6")
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	overrides := registerConfigFlags(fs)
+	if err := fs.Parse([]string{"-comment-depth-limit", "8"}); err != nil {
+		t.Fatal(err)
+	}
+
+	cfg, err := setupConfig(path, overrides)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		key    string
+		value  int
+		source string
+	}{
+		{"wrap_width", 80, "file"},
+		{"retry_count", 1, "env GN_TEXT_RETRY_COUNT"},
+		{"comment_depth_limit", 8, "flag --comment-depth-limit"},
+		{"request_timeout", 10, "default"},
+	}
+	values := make(map[string]int)
+	for _, s := range cfg.settings() {
+		if v, ok := s.Value.(*int); ok {
+			values[s.Key] = *v
+		}
+	}
+	for _, tt := range tests {
+		if values[tt.key] != tt.value {
+			t.Errorf("%s: expected %d, got %d", tt.key, tt.value, values[tt.key])
+		}
+		if source := cfg.source(tt.key); source != tt.source {
+			t.Errorf("%s: expected source %q, got %q", tt.key, tt.source, source)
+		}
+	}
+}
+
+func TestConfigInvalidValues(t *testing.T) {
+	t.Setenv("GN_TEXT_REQUEST_TIMEOUT", "soon")
+	cfg := defaultConfig()
+	if err := cfg.applyEnv(); err == nil {
+		t.Error("Expected error for non-numeric environment value")
+	}
+
+	cfg = defaultConfig()
+	cfg.RequestTimeout = 0
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "request_timeout") {
+		t.Errorf("Expected request_timeout range error, got %v", err)
+	}
+}
+
+func TestSetupSettingsFallback(t *testing.T) {
+	saved, savedTheme := settings, theme
+	t.Cleanup(func() { settings, theme = saved, savedTheme })
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	os.WriteFile(path, []byte("wrap_width: -1\n"), 0o644)
+	overrides := registerConfigFlags(flag.NewFlagSet("test", flag.ContinueOnError))
+
+	if err := setupSettings(path, overrides, "list", io.Discard); err == nil {
+		t.Error("Expected an error for an invalid config")
+	}
+	for _, command := range []string{"doctor", "config"} {
+		var stderr strings.Builder
+		if err := setupSettings(path, overrides, command, &stderr); err != nil {
+			t.Fatalf("%s: unexpected error: %v", command, err)
+		}
+		if settings.WrapWidth != 0 || settings.Path != path || !settings.Found {
+			t.Errorf("%s: expected the defaults for %s, got %+v", command, path, settings)
+		}
+		if !strings.Contains(stderr.String(), "wrap_width") {
+			t.Errorf("%s: expected the validation error on stderr, got %q", command, stderr.String())
+		}
+	}
+}
+
+func TestWriteEffectiveConfig(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Path = "/home/me/.config/gn-text/config.yaml"
+	cfg.Found = true
+	cfg.WrapWidth = 72
+	cfg.Sources["wrap_width"] = "file"
+	cfg.Keys = map[string]keyList{"down": {"j", "C-n"}}
+	cfg.Sources["keys"] = "file"
+
+	var b strings.Builder
+	cfg.writeEffective(&b)
+	out := b.String()
+
+	for _, want := range []string{
+		"# config file: /home/me/.config/gn-text/config.yaml (found)",
+		"wrap_width: 72",
+		"# file",
+		"request_timeout: 10",
+		"# default",
+		`  down: ["j", "C-n"]`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	// The output is itself a valid config file
+	var parsed Config
+	if err := yaml.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("Expected valid YAML, got error: %v", err)
+	}
+	if parsed.WrapWidth != 72 || strings.Join(parsed.Keys["down"], ",") != "j,C-n" {
+		t.Errorf("Unexpected round trip: %+v", parsed)
+	}
+}
@@ -0,0 +1,393 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"flag"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"mime"
+	"net"
+	"net/smtp"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+	"unicode/utf8"
+)
+
+// DigestConfig configures `gn-text digest`
+type DigestConfig struct {
+	Limit    int        `yaml:"limit"`    // Number of topics
+	Hours    int        `yaml:"hours"`    // Topics posted within this many hours
+	Format   string     `yaml:"format"`   // markdown or html
+	Template string     `yaml:"template"` // Go template file replacing the built-in template of the format
+	SMTP     SMTPConfig `yaml:"smtp"`
+}
+
+// SMTPConfig is the mail server `gn-text digest --email` sends through. The password is
+// read from GN_TEXT_SMTP_PASSWORD, not from the config file.
+type SMTPConfig struct {
+	Host     string   `yaml:"host"`
+	Port     int      `yaml:"port"`
+	Username string   `yaml:"username,omitempty"`
+	From     string   `yaml:"from"`
+	To       []string `yaml:"to"`
+	Subject  string   `yaml:"subject"` // With a {date} placeholder
+}
+
+// validate checks the digest settings
+func (c *DigestConfig) validate() error {
+	if c.Limit < 1 {
+		return fmt.Errorf("digest.limit must be at least 1, got %d", c.Limit)
+	}
+	if c.Hours < 1 {
+		return fmt.Errorf("digest.hours must be at least 1, got %d", c.Hours)
+	}
+	if c.Format != "markdown" && c.Format != "html" {
+		return fmt.Errorf("digest.format must be markdown or html, got %q", c.Format)
+	}
+	return nil
+}
+
+// Digest is the data digest templates are executed with
+type Digest struct {
+	Title  string // e.g. "GeekNews 다이제스트 2026-02-04"
+	Date   string // Generation date, e.g. "2026-02-04"
+	Hours  int    // Topics were posted within this many hours
+	Topics []DigestTopic
+}
+
+// DigestTopic is a topic of a digest
+type DigestTopic struct {
+	Rank       int
+	ID         string
+	Title      string
+	URL        string // GeekNews topic page
+	Link       string // External article; empty for posts without one
+	Domain     string
+	Author     string
+	Points     int
+	Comments   int
+	Posted     string // e.g. "2026-02-03 14:31"
+	Summary    string
+	TopComment *DigestComment // nil when the topic has no comments
+}
+
+// DigestComment is the top comment of a topic
+type DigestComment struct {
+	Author  string
+	Body    string
+	URL     string
+	Replies int
+}
+
+// buildDigest collects the limit topics with the most points, then comments, among the
+// topics of the front page posted within hours before now
+func buildDigest(limit, hours int, now time.Time, fetch func(string) (string, error)) (*Digest, error) {
+	articles, err := fetchSection("top", fetch)
+	if err != nil {
+		return nil, err
+	}
+
+	since := now.Add(-time.Duration(hours) * time.Hour)
+	var recent []Article
+	for _, article := range articles {
+		if article.Posted.IsZero() || !article.Posted.Before(since) {
+			recent = append(recent, article)
+		}
+	}
+	// Without the homepage there are no points or comments, and the feed order stays
+	sort.SliceStable(recent, func(i, j int) bool {
+		pi, pj := atoi(recent[i].Points), atoi(recent[j].Points)
+		if pi != pj {
+			return pi > pj
+		}
+		return atoi(recent[i].Comments) > atoi(recent[j].Comments)
+	})
+	if len(recent) > limit {
+		recent = recent[:limit]
+	}
+
+	date := now.In(geekNewsTimeZone).Format("2006-01-02")
+	digest := &Digest{Title: "GeekNews 다이제스트 " + date, Date: date, Hours: hours, Topics: make([]DigestTopic, 0, len(recent))}
+	for i, article := range recent {
+		digest.Topics = append(digest.Topics, digestTopic(i+1, article, fetch))
+	}
+	return digest, nil
+}
+
+// digestTopic returns the digest entry of an article, completed from its topic page when
+// it can be fetched
+func digestTopic(rank int, article Article, fetch func(string) (string, error)) DigestTopic {
+	topic := DigestTopic{
+		Rank:     rank,
+		ID:       extractTopicID(article.CommentsLink),
+		Title:    article.Title,
+		URL:      article.CommentsLink,
+		Link:     listField(article, "link"),
+		Domain:   article.Domain,
+		Author:   article.Author,
+		Points:   atoi(article.Points),
+		Comments: atoi(article.Comments),
+		Summary:  article.Summary,
+	}
+	if !article.Posted.IsZero() {
+		topic.Posted = article.Posted.In(geekNewsTimeZone).Format("2006-01-02 15:04")
+	}
+
+	html, err := fetch(geekNewsBaseURL + "topic?id=" + topic.ID)
+	if err != nil {
+		return topic
+	}
+	page, err := parseTopicPage(topic.ID, html)
+	if err != nil || page.Content.Title == "" {
+		return topic
+	}
+	if strings.HasPrefix(page.Content.ExternalLink, "http") {
+		topic.Link = page.Content.ExternalLink
+		topic.Domain = extractDomainFromURL(topic.Link)
+	}
+	if summary := strings.TrimSpace(page.Content.Body); summary != "" {
+		topic.Summary = summary
+	}
+	if points := atoi(page.Content.Points); points > 0 {
+		topic.Points = points
+	}
+	topic.Comments = len(page.Comments)
+	topic.TopComment = topComment(page.Comments)
+	return topic
+}
+
+// topComment returns the top-level comment with the most replies, the first of them on a
+// tie. GeekNews does not show votes on comments, so the longest thread stands in for them.
+func topComment(comments []Comment) *DigestComment {
+	var top *DigestComment
+	for i := 0; i < len(comments); i++ {
+		c := comments[i]
+		if c.Depth != 0 {
+			continue
+		}
+		replies := 0
+		for i+1 < len(comments) && comments[i+1].Depth > 0 {
+			i++
+			replies++
+		}
+		body := strings.TrimSpace(c.Body)
+		if body == "" { // Deleted
+			continue
+		}
+		if top == nil || replies > top.Replies {
+			top = &DigestComment{Author: c.Author, Body: body, URL: geekNewsBaseURL + "comment?id=" + c.ID, Replies: replies}
+		}
+	}
+	return top
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(strings.TrimSpace(s))
+	return n
+}
+
+// digestFuncs are the functions available to digest templates
+var digestFuncs = map[string]any{
+	"truncate":   truncateRunes,
+	"quote":      quoteMarkdown,
+	"paragraphs": paragraphs,
+	"lines":      func(s string) []string { return strings.Split(s, "\n") },
+}
+
+// truncateRunes shortens s to at most n characters, ending it with … when cut
+func truncateRunes(n int, s string) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	return strings.TrimSpace(string([]rune(s)[:n])) + "…"
+}
+
+// quoteMarkdown quotes every line of s as a Markdown blockquote
+func quoteMarkdown(s string) string {
+	lines := strings.Split(strings.TrimSpace(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight("> "+line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+// paragraphs splits text into its paragraphs, separated by blank lines
+func paragraphs(text string) []string {
+	var result []string
+	for _, paragraph := range strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n\n") {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			result = append(result, paragraph)
+		}
+	}
+	return result
+}
+
+// digestMarkdownTemplate is the built-in Markdown template
+const digestMarkdownTemplate = `# {{.Title}}
+
+최근 {{.Hours}}시간 동안 GeekNews에 올라온 글 {{len .Topics}}개입니다.
+{{range .Topics}}
+## {{.Rank}}. [{{.Title}}]({{if .Link}}{{.Link}}{{else}}{{.URL}}{{end}})
+
+{{if .Points}}포인트 {{.Points}} · {{end}}[{{if .Comments}}댓글 {{.Comments}}개{{else}}GeekNews{{end}}]({{.URL}}){{if .Link}} · {{.Domain}}{{end}}{{if .Author}} · {{.Author}}{{end}}
+{{with .Summary}}
+{{truncate 300 .}}
+{{end}}{{with .TopComment}}
+**{{.Author}}**님의 댓글:
+
+{{quote (truncate 400 .Body)}}
+{{end}}{{end}}`
+
+// digestHTMLTemplate is the built-in HTML template
+const digestHTMLTemplate = `<!DOCTYPE html>
+<html lang="ko">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+</head>
+<body style="font-family: sans-serif; max-width: 40em; line-height: 1.5">
+<h1>{{.Title}}</h1>
+<p>최근 {{.Hours}}시간 동안 GeekNews에 올라온 글 {{len .Topics}}개입니다.</p>
+{{range .Topics}}
+<h2>{{.Rank}}. <a href="{{if .Link}}{{.Link}}{{else}}{{.URL}}{{end}}">{{.Title}}</a></h2>
+<p style="color: #666">{{if .Points}}포인트 {{.Points}} · {{end}}<a href="{{.URL}}">{{if .Comments}}댓글 {{.Comments}}개{{else}}GeekNews{{end}}</a>{{if .Link}} · {{.Domain}}{{end}}{{if .Author}} · {{.Author}}{{end}}</p>
+{{range paragraphs (truncate 300 .Summary)}}<p>{{range $i, $line := lines .}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+{{end}}{{with .TopComment}}<blockquote style="border-left: 3px solid #ccc; margin-left: 0; padding-left: 1em">
+<p><a href="{{.URL}}">{{.Author}}</a>님의 댓글:</p>
+{{range paragraphs (truncate 400 .Body)}}<p>{{range $i, $line := lines .}}{{if $i}}<br>{{end}}{{$line}}{{end}}</p>
+{{end}}</blockquote>
+{{end}}{{end}}
+</body>
+</html>
+`
+
+// renderDigest executes the built-in template of format, or the template file at path,
+// with digest. HTML templates escape what they insert.
+func renderDigest(w io.Writer, digest *Digest, format, path string) error {
+	text := digestMarkdownTemplate
+	if format == "html" {
+		text = digestHTMLTemplate
+	}
+	name := "digest"
+	if path != "" {
+		file, err := expandHome(path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		text, name = string(data), path
+	}
+
+	if format == "html" {
+		tmpl, err := htmltemplate.New(name).Funcs(digestFuncs).Parse(text)
+		if err != nil {
+			return err
+		}
+		return tmpl.Execute(w, digest)
+	}
+	tmpl, err := template.New(name).Funcs(digestFuncs).Parse(text)
+	if err != nil {
+		return err
+	}
+	return tmpl.Execute(w, digest)
+}
+
+// digestMessage returns a digest as an email message
+func digestMessage(config SMTPConfig, subject, format string, body []byte, now time.Time) []byte {
+	contentType := "text/plain"
+	if format == "html" {
+		contentType = "text/html"
+	}
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(config.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.BEncoding.Encode("UTF-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: %s; charset=UTF-8\r\n", contentType)
+	msg.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString(body)
+	for len(encoded) > 76 {
+		msg.WriteString(encoded[:76] + "\r\n")
+		encoded = encoded[76:]
+	}
+	msg.WriteString(encoded + "\r\n")
+	return msg.Bytes()
+}
+
+// sendDigest sends a rendered digest through the configured SMTP server, authenticating
+// when a username is set
+func sendDigest(config SMTPConfig, format string, body []byte, now time.Time) error {
+	if config.Host == "" || config.From == "" || len(config.To) == 0 {
+		return errors.New("digest.smtp needs host, from and to (see README, Daily Digest)")
+	}
+	port := config.Port
+	if port == 0 {
+		port = 587
+	}
+	var auth smtp.Auth
+	if config.Username != "" {
+		auth = smtp.PlainAuth("", config.Username, os.Getenv("GN_TEXT_SMTP_PASSWORD"), config.Host)
+	}
+	subject := strings.ReplaceAll(config.Subject, "{date}", now.In(geekNewsTimeZone).Format("2006-01-02"))
+	addr := net.JoinHostPort(config.Host, strconv.Itoa(port))
+	return smtp.SendMail(addr, auth, config.From, config.To, digestMessage(config, subject, format, body, now))
+}
+
+// runDigest implements `gn-text digest`, which renders the top topics of the day to
+// Markdown or HTML, or mails them
+func runDigest(args []string, w io.Writer) int {
+	config := settings.Digest
+	fs := flag.NewFlagSet("digest", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.IntVar(&config.Limit, "limit", config.Limit, "Number of topics")
+	fs.IntVar(&config.Hours, "hours", config.Hours, "Include topics posted within this many hours")
+	fs.StringVar(&config.Format, "format", config.Format, "Output format: markdown or html")
+	fs.StringVar(&config.Template, "template", config.Template, "Go template file to render the digest with")
+	output := fs.String("o", "-", "Write the digest to this file (- for stdout)")
+	email := fs.Bool("email", false, "Send the digest through the SMTP server of the config file")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if err := config.validate(); err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", strings.TrimPrefix(err.Error(), "digest."))
+		return 2
+	}
+
+	now := time.Now()
+	digest, err := buildDigest(config.Limit, config.Hours, now, fetchCached)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	var body bytes.Buffer
+	if err := renderDigest(&body, digest, config.Format, config.Template); err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+
+	switch {
+	case *email:
+		err = sendDigest(config.SMTP, config.Format, body.Bytes(), now)
+	case *output == "-":
+		_, err = w.Write(body.Bytes())
+	default:
+		err = os.WriteFile(*output, body.Bytes(), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	return 0
+}
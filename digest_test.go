@@ -0,0 +1,188 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testDigest builds the digest of the test fixtures at goldenTime
+func testDigest(t *testing.T, hours int) *Digest {
+	fetch := testdataFetchTime(t)
+	digest, err := buildDigest(10, hours, goldenTime, func(url string) (string, error) {
+		data, _, err := fetch(url)
+		return data, err
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	return digest
+}
+
+func TestBuildDigest(t *testing.T) {
+	digest := testDigest(t, 24)
+	if digest.Date != "2026-02-04" || len(digest.Topics) != 2 {
+		t.Fatalf("Expected 2 topics on 2026-02-04, got %d on %s", len(digest.Topics), digest.Date)
+	}
+	top := digest.Topics[0]
+	if top.Rank != 1 || top.ID != "26364" || top.Points != 25 || top.Comments == 0 {
+		t.Errorf("Expected topic 26364 with its points first, got %+v", top)
+	}
+	if top.Link != "https://www.anthropic.com/research/AI-assistance-coding-skills" || top.Summary == "" {
+		t.Errorf("Expected the link and summary from the topic page, got %q, %q", top.Link, top.Summary)
+	}
+	if top.TopComment == nil || top.TopComment.Author == "" || top.TopComment.Body == "" {
+		t.Errorf("Expected a top comment, got %+v", top.TopComment)
+	}
+
+	// Topic 26363 was posted 22½ hours before goldenTime
+	if recent := testDigest(t, 22); len(recent.Topics) != 1 || recent.Topics[0].ID != "26364" {
+		t.Errorf("Expected only topic 26364 within 22 hours, got %+v", recent.Topics)
+	}
+}
+
+func TestTopComment(t *testing.T) {
+	comments := []Comment{
+		{ID: "1", Author: "a", Body: "one reply", Depth: 0},
+		{ID: "2", Depth: 1},
+		{ID: "3", Author: "b", Body: "", Depth: 0}, // Deleted, with the most replies
+		{ID: "4", Depth: 1}, {ID: "5", Depth: 1}, {ID: "6", Depth: 2},
+		{ID: "7", Author: "c", Body: "two replies", Depth: 0},
+		{ID: "8", Depth: 1}, {ID: "9", Depth: 2},
+		{ID: "10", Author: "d", Body: "two replies, later", Depth: 0},
+		{ID: "11", Depth: 1}, {ID: "12", Depth: 1},
+	}
+	top := topComment(comments)
+	if top == nil || top.Author != "c" || top.Replies != 2 || top.URL != geekNewsBaseURL+"comment?id=7" {
+		t.Errorf("Expected the first comment with the most replies, got %+v", top)
+	}
+	if topComment(nil) != nil {
+		t.Error("Expected no top comment without comments")
+	}
+}
+
+func TestRenderDigest(t *testing.T) {
+	digest := testDigest(t, 24)
+	digest.Topics[0].Title = "A <b> & B"
+
+	var markdown bytes.Buffer
+	if err := renderDigest(&markdown, digest, "markdown", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	for _, want := range []string{"# GeekNews 다이제스트 2026-02-04", "## 1. [A <b> & B](https://www.anthropic.com/", "포인트 25 · [댓글 ", "님의 댓글:\n\n> "} {
+		if !strings.Contains(markdown.String(), want) {
+			t.Errorf("Expected %q in the Markdown digest, got:\n%s", want, markdown.String())
+		}
+	}
+
+	var html bytes.Buffer
+	if err := renderDigest(&html, digest, "html", ""); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.Contains(html.String(), "A &lt;b&gt; &amp; B</a></h2>") || !strings.Contains(html.String(), "<blockquote") {
+		t.Errorf("Expected an escaped title and a quoted comment, got:\n%s", html.String())
+	}
+
+	path := filepath.Join(t.TempDir(), "digest.tmpl")
+	os.WriteFile(path, []byte(`{{range .Topics}}{{.Rank}} {{.ID}} {{truncate 3 .Title}}{{"\n"}}{{end}}`), 0o644)
+	var custom bytes.Buffer
+	if err := renderDigest(&custom, digest, "markdown", path); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if custom.String() != "1 26364 A <…\n2 26363 Tod…\n" {
+		t.Errorf("Unexpected custom digest: %q", custom.String())
+	}
+}
+
+// fakeSMTPServer accepts one message on a local port and sends what it received on the channel
+func fakeSMTPServer(t *testing.T) (host string, port int, received chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { listener.Close() })
+	received = make(chan string, 1)
+
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+		var transcript strings.Builder
+		reply("220 localhost ESMTP")
+		for {
+			line, err := r.ReadString('\n')
+			if err != nil {
+				return
+			}
+			transcript.WriteString(line)
+			switch cmd := strings.ToUpper(strings.TrimSpace(line)); {
+			case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+				reply("250 localhost")
+			case cmd == "DATA":
+				reply("354 go ahead")
+				for {
+					line, err := r.ReadString('\n')
+					if err != nil || line == ".\r\n" {
+						break
+					}
+					transcript.WriteString(line)
+				}
+				reply("250 queued")
+			case cmd == "QUIT":
+				reply("221 bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("250 ok")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, received
+}
+
+func TestSendDigest(t *testing.T) {
+	host, port, received := fakeSMTPServer(t)
+	config := SMTPConfig{
+		Host:    host,
+		Port:    port,
+		From:    "bot@example.com",
+		To:      []string{"team@example.com", "lead@example.com"},
+		Subject: "GeekNews 다이제스트 {date}",
+	}
+	body := []byte("<h1>다이제스트</h1>")
+	if err := sendDigest(config, "html", body, goldenTime); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	transcript := <-received
+	for _, want := range []string{
+		"MAIL FROM:<bot@example.com>", "RCPT TO:<team@example.com>", "RCPT TO:<lead@example.com>",
+		"To: team@example.com, lead@example.com", "Subject: =?UTF-8?b?", "Content-Type: text/html; charset=UTF-8",
+	} {
+		if !strings.Contains(transcript, want) {
+			t.Errorf("Expected %q in the SMTP session, got:\n%s", want, transcript)
+		}
+	}
+	_, encoded, _ := strings.Cut(transcript, "\r\n\r\n") // Body between the headers and QUIT
+	encoded, _, _ = strings.Cut(encoded, "QUIT")
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(encoded, "\r\n", ""))
+	if err != nil || !bytes.Equal(decoded, body) {
+		t.Errorf("Expected the body in base64, got %q (%v)", decoded, err)
+	}
+
+	config.Host = ""
+	if err := sendDigest(config, "html", body, goldenTime); err == nil {
+		t.Error("Expected an error without a host")
+	}
+}
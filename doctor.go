@@ -0,0 +1,416 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gdamore/tcell/v2/terminfo"
+	"golang.org/x/term"
+)
+
+// doctorTopicID is a long-lived topic whose page and external article are used as a known-good sample
+const doctorTopicID = "26364"
+
+// doctor collects the output of the self-check and counts failed checks
+type doctor struct {
+	out      *tabwriter.Writer
+	base     string       // GeekNews URL checked online
+	report   *ParseReport // Selector hits of every page parsed so far
+	failures int
+}
+
+// runDoctor implements `gn-text doctor`. With file arguments it runs offline
+// against saved pages; otherwise it checks news.hada.io directly.
+func runDoctor(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("doctor", flag.ContinueOnError)
+	fs.SetOutput(w)
+	fs.Usage = func() {
+		fmt.Fprintln(w, "Usage: gn-text doctor [saved-feed.xml | saved-page.html ...]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	d := &doctor{out: tabwriter.NewWriter(w, 0, 4, 2, ' ', 0), base: geekNewsBaseURL, report: newParseReport()}
+	d.line("gn-text doctor")
+	d.line("version\t%s (%s %s/%s)", version, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	d.line("time\t%s", time.Now().Format(time.RFC3339))
+
+	if fs.NArg() > 0 {
+		for _, path := range fs.Args() {
+			d.checkFile(path)
+		}
+	} else {
+		d.checkOnline()
+	}
+	d.checkSelectors()
+
+	d.checkTerminal()
+	d.checkCache()
+
+	d.section("result")
+	if d.failures == 0 {
+		d.line("status\tok")
+	} else {
+		d.line("status\t%d check(s) failed", d.failures)
+	}
+	d.out.Flush()
+
+	if d.failures > 0 {
+		return 1
+	}
+	return 0
+}
+
+func (d *doctor) section(name string) {
+	// Flush so columns are aligned per section
+	d.out.Flush()
+	d.line("")
+	d.line("[%s]", name)
+}
+
+func (d *doctor) line(format string, args ...any) {
+	fmt.Fprintf(d.out, format+"\n", args...)
+}
+
+func (d *doctor) fail(format string, args ...any) {
+	d.failures++
+	d.line(format, args...)
+}
+
+// checkOnline fetches the homepage, feed, a known topic, its author's pages, one of its
+// comments and its article, and runs the matching parsers on each. Every check runs on
+// its own; one that depends on a failed check is reported as skipped.
+func (d *doctor) checkOnline() {
+	d.section("list")
+	if html, ok := d.fetch(d.base); ok {
+		d.checkList(html)
+	}
+
+	d.section("feed")
+	if feed, ok := d.fetch(geekNewsRSSURL); ok {
+		d.checkFeed(feed)
+	}
+
+	d.section("topic " + doctorTopicID)
+	var content *TopicContent
+	var comments []Comment
+	if html, ok := d.fetch(d.base + "topic?id=" + doctorTopicID); ok {
+		content, comments = d.checkTopic(html)
+	}
+	if content == nil {
+		d.fail("user\tskipped: topic %s unavailable", doctorTopicID)
+		d.fail("comment\tskipped: topic %s unavailable", doctorTopicID)
+		d.fail("articletext\tskipped: topic %s unavailable", doctorTopicID)
+		return
+	}
+
+	if content.AuthorID == "" {
+		d.section("user")
+		d.fail("user\tskipped: no author on topic %s", doctorTopicID)
+	} else {
+		d.section("user " + content.AuthorID)
+		query := "?id=" + url.QueryEscape(content.AuthorID)
+		if html, ok := d.fetch(d.base + "user" + query); ok {
+			d.checkUser(html)
+		}
+		if html, ok := d.fetch(d.base + "user_topics" + query); ok {
+			d.checkList(html)
+		}
+		if html, ok := d.fetch(d.base + "user_comments" + query); ok {
+			d.checkUserComments(html)
+		}
+	}
+
+	if len(comments) == 0 {
+		d.section("comment")
+		d.line("comment\tskipped: no comments on topic %s", doctorTopicID)
+	} else {
+		d.section("comment " + comments[0].ID)
+		if html, ok := d.fetch(d.base + "comment?id=" + comments[0].ID); ok {
+			d.checkCommentPage(html)
+		}
+	}
+
+	d.section("extractor")
+	link := content.ExternalLink
+	if !strings.HasPrefix(link, "http") {
+		d.fail("articletext\tskipped: no external link on topic %s", doctorTopicID)
+		return
+	}
+	start := time.Now()
+	text, err := extractArticleText(link)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	switch {
+	case err != nil:
+		d.fail("articletext\tFAIL\t%s\t%s\t%v", elapsed, link, err)
+	case strings.TrimSpace(text) == "":
+		d.fail("articletext\tFAIL\t%s\t%s\tno text", elapsed, link)
+	default:
+		d.line("articletext\tok\t%s\t%d chars\t%s", elapsed, len([]rune(text)), link)
+	}
+}
+
+// fetch performs a GET and reports status, timing and size
+func (d *doctor) fetch(url string) (string, bool) {
+	client := &http.Client{Timeout: time.Duration(settings.RequestTimeout) * time.Second}
+	start := time.Now()
+	res, err := client.Get(url)
+	if err != nil {
+		d.fail("GET %s\tFAIL\t%s\t%v", url, time.Since(start).Round(time.Millisecond), err)
+		return "", false
+	}
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	elapsed := time.Since(start).Round(time.Millisecond)
+	if err != nil {
+		d.fail("GET %s\t%d\t%s\tread failed: %v", url, res.StatusCode, elapsed, err)
+		return "", false
+	}
+	if res.StatusCode != http.StatusOK {
+		d.fail("GET %s\t%d\t%s\t%d bytes", url, res.StatusCode, elapsed, len(body))
+		return "", false
+	}
+	d.line("GET %s\t%d\t%s\t%d bytes", url, res.StatusCode, elapsed, len(body))
+	return string(body), true
+}
+
+// checkFile runs the parsers matching a saved page: a feed, a topic, a topic list, a
+// comments page or a user profile. Anything else is checked as a topic page, so a changed
+// topic layout is reported rather than skipped.
+func (d *doctor) checkFile(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		d.section("file " + filepath.Base(path))
+		d.fail("read\tFAIL\t%v", err)
+		return
+	}
+
+	content := string(data)
+	trimmed := strings.TrimSpace(content)
+	var kind string
+	var check func(string)
+	switch {
+	case strings.HasPrefix(trimmed, "<?xml") || strings.HasPrefix(trimmed, "<feed"):
+		kind, check = "feed", d.checkFeed
+	case strings.Contains(content, "topic_row"):
+		kind, check = "list", d.checkList
+	case strings.Contains(content, "topictitle") || strings.Contains(content, "comment_thread"):
+		kind, check = "topic", func(html string) { d.checkTopic(html) }
+	case strings.Contains(content, "comment_row"):
+		kind, check = "comments", func(html string) {
+			d.checkUserComments(html)
+			d.checkCommentPage(html)
+		}
+	case strings.Contains(content, "<table"):
+		kind, check = "user", d.checkUser
+	default:
+		kind, check = "topic", func(html string) { d.checkTopic(html) }
+	}
+	d.section(kind + " " + filepath.Base(path))
+	d.line("file\t%s\t%d bytes", path, len(data))
+	check(content)
+}
+
+func (d *doctor) checkFeed(xmlData string) {
+	articles, err := parseGeekNewsRSS(xmlData)
+	if err != nil {
+		d.fail("parseGeekNewsRSS\tFAIL\t%v", err)
+		return
+	}
+	if len(articles) == 0 {
+		d.fail("parseGeekNewsRSS\tFAIL\t0 articles")
+		return
+	}
+	d.line("parseGeekNewsRSS\tok\t%d articles", len(articles))
+}
+
+// checkList runs the topic list parser, used for sections and a user's topics
+func (d *doctor) checkList(html string) {
+	articles, report, err := parseGeekNewsTopicList(html)
+	if err != nil {
+		d.fail("parseGeekNewsTopicList\tFAIL\t%v", err)
+		return
+	}
+	d.report.Merge(report)
+	if len(articles) == 0 {
+		d.fail("parseGeekNewsTopicList\tFAIL\t0 topics")
+		return
+	}
+	d.line("parseGeekNewsTopicList\tok\t%d topics", len(articles))
+}
+
+// checkTopic runs every topic page parser and returns the topic and its comments,
+// or a nil topic if the page cannot be parsed
+func (d *doctor) checkTopic(html string) (*TopicContent, []Comment) {
+	content, report, err := parseGeekNewsTopicContent(html)
+	if err != nil {
+		d.fail("parseGeekNewsTopicContent\tFAIL\t%v", err)
+		return nil, nil
+	}
+	d.report.Merge(report)
+	d.line("parseGeekNewsTopicContent\tok\ttitle=%q author=%q points=%q body=%d chars",
+		content.Title, content.Author, content.Points, len([]rune(content.Body)))
+
+	link, _, _, err := parseGeekNewsTopicLink(html)
+	if err != nil {
+		d.fail("parseGeekNewsTopicLink\tFAIL\t%v", err)
+	} else {
+		d.line("parseGeekNewsTopicLink\tok\t%s", link)
+	}
+
+	comments, commentsReport, err := parseGeekNewsComments(html)
+	if err != nil {
+		d.fail("parseGeekNewsComments\tFAIL\t%v", err)
+	} else {
+		d.report.Merge(commentsReport)
+		d.line("parseGeekNewsComments\tok\t%d comments", len(comments))
+	}
+	return content, comments
+}
+
+// checkUser runs the user profile parser
+func (d *doctor) checkUser(html string) {
+	profile, report, err := parseGeekNewsUserProfile(html)
+	if err != nil {
+		d.fail("parseGeekNewsUserProfile\tFAIL\t%v", err)
+		return
+	}
+	d.report.Merge(report)
+	d.line("parseGeekNewsUserProfile\tok\tid=%q karma=%q created=%q", profile.ID, profile.Karma, profile.Created)
+}
+
+// checkUserComments runs the parser of a user's comments page
+func (d *doctor) checkUserComments(html string) {
+	comments, report, err := parseGeekNewsUserComments(html)
+	if err != nil {
+		d.fail("parseGeekNewsUserComments\tFAIL\t%v", err)
+		return
+	}
+	d.report.Merge(report)
+	d.line("parseGeekNewsUserComments\tok\t%d comments", len(comments))
+}
+
+// checkCommentPage runs the parser finding the topic of a comment page
+func (d *doctor) checkCommentPage(html string) {
+	link, report, err := parseGeekNewsCommentTopic(html)
+	if err != nil {
+		d.fail("parseGeekNewsCommentTopic\tFAIL\t%v", err)
+		return
+	}
+	d.report.Merge(report)
+	if link == "" {
+		d.fail("parseGeekNewsCommentTopic\tFAIL\tno topic link")
+		return
+	}
+	d.line("parseGeekNewsCommentTopic\tok\t%s", link)
+}
+
+// checkSelectors prints the hits of every registered selector over all parsed pages
+func (d *doctor) checkSelectors() {
+	d.section("selectors")
+	for _, sel := range selectorRegistry {
+		kind := "optional"
+		if sel.Required {
+			kind = "required"
+		}
+		hits, evaluated := d.report.Hits[sel.Name]
+		switch {
+		case !evaluated:
+			d.line("%s\t%s\t-\tnot evaluated\t%s", sel.Name, kind, sel.Query)
+		case hits == 0 && sel.Required:
+			d.fail("%s\t%s\t0\tMISSING\t%s", sel.Name, kind, sel.Query)
+		case hits == 0:
+			d.line("%s\t%s\t0\tmissing\t%s", sel.Name, kind, sel.Query)
+		default:
+			d.line("%s\t%s\t%d\tok\t%s", sel.Name, kind, hits, sel.Query)
+		}
+	}
+}
+
+// checkTerminal reports terminal size and color capability
+func (d *doctor) checkTerminal() {
+	d.section("terminal")
+	fd := int(os.Stdout.Fd())
+	if term.IsTerminal(fd) {
+		width, height, err := term.GetSize(fd)
+		if err != nil {
+			d.line("stdout\ttty\tsize unavailable: %v", err)
+		} else {
+			d.line("stdout\ttty\t%dx%d", width, height)
+		}
+	} else {
+		d.line("stdout\tnot a tty\tfallback width %d", getTerminalWidth())
+	}
+
+	termName := os.Getenv("TERM")
+	colors := "unknown"
+	if ti, err := terminfo.LookupTerminfo(termName); err == nil {
+		colors = fmt.Sprintf("%d", ti.Colors)
+	}
+	d.line("TERM\t%s\tcolors %s", envOrUnset("TERM"), colors)
+	d.line("COLORTERM\t%s", envOrUnset("COLORTERM"))
+	d.line("NO_COLOR\t%s", envOrUnset("NO_COLOR"))
+}
+
+// checkCache reports the state of the cache directory
+func (d *doctor) checkCache() {
+	d.section("cache")
+	dir, err := cacheDir()
+	if err != nil {
+		d.line("dir\tunavailable\t%v", err)
+		return
+	}
+
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		d.line("dir\t%s\tnot created", dir)
+		return
+	}
+
+	var files int
+	var size int64
+	err = filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if info, err := entry.Info(); err == nil && !entry.IsDir() {
+			files++
+			size += info.Size()
+		}
+		return nil
+	})
+	if err != nil {
+		d.fail("dir\t%s\tFAIL\t%v", dir, err)
+		return
+	}
+	d.line("dir\t%s\t%d files\t%d bytes", dir, files, size)
+	d.line("ttl\tmemory %ds\tdisk %ds", settings.CacheTTLMemory, settings.CacheTTLDisk)
+}
+
+// cacheDir returns the gn-text cache directory (~/.cache/gn-text or OS equivalent)
+func cacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "gn-text"), nil
+}
+
+func envOrUnset(key string) string {
+	if value, ok := os.LookupEnv(key); ok {
+		return value
+	}
+	return "(unset)"
+}
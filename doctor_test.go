@@ -0,0 +1,124 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/tabwriter"
+)
+
+func TestRunDoctorOffline(t *testing.T) {
+	var out bytes.Buffer
+	code := runDoctor([]string{"testdata/geeknews_feed.xml", "testdata/geeknews_topic_full.html"}, &out)
+
+	output := out.String()
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d\n%s", code, output)
+	}
+
+	for _, want := range []string{
+		"parseGeekNewsRSS",
+		"2 articles",
+		"parseGeekNewsTopicContent",
+		"parseGeekNewsComments",
+		selCommentRow.Name,
+		"[terminal]",
+		"[cache]",
+		"status",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected doctor output to contain %q\n%s", want, output)
+		}
+	}
+	if strings.Contains(output, "MISSING") {
+		t.Errorf("Expected no missing required selectors\n%s", output)
+	}
+}
+
+func TestRunDoctorOfflinePages(t *testing.T) {
+	var out bytes.Buffer
+	code := runDoctor([]string{"testdata/geeknews_homepage_topics.html", "testdata/geeknews_user.html", "testdata/geeknews_user_comments.html"}, &out)
+
+	output := out.String()
+	if code != 0 {
+		t.Fatalf("Expected exit code 0, got %d\n%s", code, output)
+	}
+	for _, want := range []string{
+		"[list geeknews_homepage_topics.html]",
+		"parseGeekNewsTopicList",
+		"[user geeknews_user.html]",
+		"parseGeekNewsUserProfile",
+		"[comments geeknews_user_comments.html]",
+		"parseGeekNewsUserComments",
+		"parseGeekNewsCommentTopic",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Expected doctor output to contain %q\n%s", want, output)
+		}
+	}
+	// The required selectors of these pages are evaluated and found
+	for _, sel := range []Selector{selTopicRowTopic, selUserRow, selCommentPageTopic} {
+		for _, line := range strings.Split(output, "\n") {
+			if strings.HasPrefix(line, sel.Name+" ") && !strings.Contains(line, " ok ") {
+				t.Errorf("Expected hits for %s, got %q", sel.Name, line)
+			}
+		}
+	}
+}
+
+func TestRunDoctorOfflineChangedLayout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "topic.html")
+	os.WriteFile(path, []byte(`<html><body><div class="topic"><h1>Renamed</h1></div></body></html>`), 0o644)
+
+	var out bytes.Buffer
+	code := runDoctor([]string{path}, &out)
+
+	if code == 0 {
+		t.Fatalf("Expected failure for a page without topic selectors\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "MISSING") {
+		t.Errorf("Expected missing selector report\n%s", out.String())
+	}
+}
+
+func TestRunDoctorMissingFile(t *testing.T) {
+	var out bytes.Buffer
+	if code := runDoctor([]string{"testdata/does_not_exist.html"}, &out); code == 0 {
+		t.Error("Expected failure for a missing file")
+	}
+}
+
+func TestDoctorOnlineChecksRunIndependently(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/rss/news" {
+			http.ServeFile(w, r, "testdata/geeknews_feed.xml")
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+	savedRSS := geekNewsRSSURL
+	geekNewsRSSURL = server.URL + "/rss/news"
+	defer func() { geekNewsRSSURL = savedRSS }()
+
+	var out bytes.Buffer
+	d := &doctor{out: tabwriter.NewWriter(&out, 0, 4, 2, ' ', 0), base: server.URL + "/", report: newParseReport()}
+	d.checkOnline()
+	d.out.Flush()
+
+	// The homepage and topic are down, but the feed is still checked
+	output := out.String()
+	if !strings.Contains(strings.Join(strings.Fields(output), " "), "parseGeekNewsRSS ok 2 articles") {
+		t.Errorf("Expected the feed to be checked\n%s", output)
+	}
+	if !strings.Contains(output, "skipped: topic "+doctorTopicID+" unavailable") {
+		t.Errorf("Expected the checks needing the topic to be skipped\n%s", output)
+	}
+	if d.failures != 5 {
+		t.Errorf("Expected 5 failures (homepage, topic and 3 skipped checks), got %d\n%s", d.failures, output)
+	}
+}
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/rivo/tview"
+)
+
+// exportPlaceholderRegex matches a placeholder of export_filename, e.g. "{title}"
+var exportPlaceholderRegex = regexp.MustCompile(`\{([a-z]*)\}`)
+
+// exportPlaceholders are the placeholders export_filename can use
+var exportPlaceholders = []string{"id", "title", "author", "date"}
+
+// exportFilename expands the placeholders of pattern for a topic exported at now
+func exportFilename(pattern string, content *TopicContent, topicID string, now time.Time) (string, error) {
+	var err error
+	name := exportPlaceholderRegex.ReplaceAllStringFunc(pattern, func(placeholder string) string {
+		switch placeholder[1 : len(placeholder)-1] {
+		case "id":
+			return topicID
+		case "title":
+			return slugify(content.Title)
+		case "author":
+			return slugify(content.Author)
+		case "date":
+			return now.Format("2006-01-02")
+		}
+		err = fmt.Errorf("export_filename: unknown placeholder %s (available: {%s})", placeholder, strings.Join(exportPlaceholders, "}, {"))
+		return placeholder
+	})
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(name) == "" {
+		return "", fmt.Errorf("export_filename %q gives an empty file name", pattern)
+	}
+	return name, nil
+}
+
+// slugify turns a title into a file name part: letters and digits of any script, with
+// every other run of characters replaced by a dash, at most 60 characters long
+func slugify(title string) string {
+	var b strings.Builder
+	dash := false
+	n := 0
+	for _, r := range strings.ToLower(title) {
+		if n >= 60 {
+			break
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			dash = b.Len() > 0
+			continue
+		}
+		if dash {
+			b.WriteRune('-')
+			n++
+			dash = false
+		}
+		b.WriteRune(r)
+		n++
+	}
+	return b.String()
+}
+
+// exportPath returns the file a topic is exported to: export_filename in export_dir
+func exportPath(content *TopicContent, topicID string, now time.Time) (string, error) {
+	name, err := exportFilename(settings.ExportFilename, content, topicID, now)
+	if err != nil {
+		return "", err
+	}
+	dir, err := expandHome(settings.ExportDir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// expandHome expands a leading ~/ of path to the home directory
+func expandHome(path string) (string, error) {
+	rest, ok := strings.CutPrefix(path, "~/")
+	if !ok {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, rest), nil
+}
+
+// topicMarkdown writes a topic as Markdown: its title, links, metadata and summary, the
+// article text if given, and the comments as blockquotes nested by depth
+func topicMarkdown(page *TopicPage, article string, now time.Time) string {
+	var b strings.Builder
+	content := page.Content
+	topicURL := geekNewsBaseURL + "topic?id=" + page.ID
+
+	fmt.Fprintf(&b, "# %s\n\n", content.Title)
+	if strings.HasPrefix(content.ExternalLink, "http") {
+		fmt.Fprintf(&b, "- 원문: <%s>\n", content.ExternalLink)
+	}
+	fmt.Fprintf(&b, "- GeekNews: <%s>\n", topicURL)
+	var meta []string
+	for _, value := range []string{content.Author, content.Time} {
+		if value != "" {
+			meta = append(meta, value)
+		}
+	}
+	if content.Points != "" {
+		meta = append(meta, content.Points+"P")
+	}
+	if len(meta) > 0 {
+		fmt.Fprintf(&b, "- 작성: %s\n", strings.Join(meta, " · "))
+	}
+	fmt.Fprintf(&b, "- 내보낸 날짜: %s\n", now.Format("2006-01-02"))
+
+	if body := strings.TrimSpace(content.Body); body != "" {
+		fmt.Fprintf(&b, "\n## 요약\n\n%s\n", body)
+	}
+	if article = strings.TrimSpace(article); article != "" {
+		fmt.Fprintf(&b, "\n## 기사\n\n%s\n", article)
+	}
+
+	fmt.Fprintf(&b, "\n## 댓글 %d개\n", len(page.Comments))
+	for _, comment := range page.Comments {
+		b.WriteString("\n")
+		writeCommentMarkdown(&b, comment)
+	}
+	return b.String()
+}
+
+// writeCommentMarkdown writes a comment as a blockquote nested one level deeper than its parent
+func writeCommentMarkdown(b *strings.Builder, comment Comment) {
+	quote := strings.Repeat("> ", comment.Depth+1)
+	header := quote + "**" + comment.Author + "**"
+	if comment.Time != "" {
+		header += " (" + comment.Time + ")"
+	}
+	b.WriteString(header + "\n" + strings.TrimRight(quote, " ") + "\n")
+
+	body := strings.TrimSpace(comment.Body)
+	if body == "" {
+		body = "*삭제됨*"
+	}
+	for _, line := range strings.Split(body, "\n") {
+		b.WriteString(strings.TrimRight(quote+strings.TrimSpace(line), " ") + "\n")
+	}
+}
+
+// exportTopic writes page and article to the export file of the topic, creating its
+// directory, and returns the file's path
+func exportTopic(page *TopicPage, article string) (string, error) {
+	now := time.Now()
+	path, err := exportPath(page.Content, page.ID, now)
+	if err != nil {
+		return "", err
+	}
+	if dir := filepath.Dir(path); dir != "." {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return "", err
+		}
+	}
+	if err := os.WriteFile(path, []byte(topicMarkdown(page, article, now)), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// fetchExportPage fetches a topic page for export, falling back to its bookmarked copy
+func fetchExportPage(topicID string, saved bookmarkCopy) (*TopicPage, error) {
+	page, err := fetchTopicPage(topicID)
+	if err != nil {
+		if page, savedErr := saved.page(); savedErr == nil {
+			return page, nil
+		}
+		return nil, err
+	}
+	return page, nil
+}
+
+// exportArticle returns the article text of a topic for export, from its bookmarked copy
+// if it cannot be extracted. Topics without an external article have none.
+func exportArticle(page *TopicPage, saved bookmarkCopy) (string, error) {
+	link := page.Content.ExternalLink
+	if !strings.HasPrefix(link, "http") {
+		return "", nil
+	}
+	article, err := extractArticleText(link)
+	if err != nil {
+		if text, ok := saved.text(); ok {
+			return text, nil
+		}
+		return "", fmt.Errorf("%s: %w", link, err)
+	}
+	return article, nil
+}
+
+// runExport implements `gn-text export`, which writes a topic to a Markdown file
+func runExport(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.SetOutput(w)
+	output := fs.String("o", "", "Write to this file instead of export_dir/export_filename (- for stdout)")
+	noArticle := fs.Bool("no-article", false, "Leave out the extracted article text")
+	fs.Usage = func() {
+		fmt.Fprintln(w, "Usage: gn-text export [flags] <topic ID or URL>")
+		fs.PrintDefaults()
+	}
+	topics, ok := parseInterspersed(fs, args)
+	if !ok {
+		return 2
+	}
+	if len(topics) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	topicID, err := resolveTopicID(topics[0], fetchCached)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 2
+	}
+	// Subcommands run without the UI's state; the bookmarks are only read, for their copies
+	var saved bookmarkCopy
+	if path, err := bookmarksPath(); err == nil {
+		if store, err := loadBookmarks(path); err == nil {
+			saved = store.copyOf(topicID)
+		}
+	}
+	page, err := fetchExportPage(topicID, saved)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	article := ""
+	if !*noArticle {
+		if article, err = exportArticle(page, saved); err != nil {
+			fmt.Fprintln(os.Stderr, "gn-text: article left out:", err)
+		}
+	}
+
+	var path string
+	switch *output {
+	case "-":
+		_, err = io.WriteString(w, topicMarkdown(page, article, time.Now()))
+	case "":
+		path, err = exportTopic(page, article)
+	default:
+		path = *output
+		err = os.WriteFile(path, []byte(topicMarkdown(page, article, time.Now())), 0o644)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	if path != "" {
+		fmt.Fprintln(w, path)
+	}
+	return 0
+}
+
+// exportSelected exports the current topic to Markdown in the background
+func (s *session) exportSelected() {
+	article, ok := s.selectedArticle()
+	topicID := extractTopicID(article.CommentsLink)
+	if !ok || topicID == "" {
+		return
+	}
+	s.setStatus("Markdown으로 내보내는 중...")
+	saved := bookmarks.copyOf(topicID) // The bookmark list is only read on the UI goroutine
+
+	go func() {
+		var path string
+		var articleErr error
+		page, err := fetchExportPage(topicID, saved)
+		if err == nil {
+			var text string
+			text, articleErr = exportArticle(page, saved)
+			path, err = exportTopic(page, text)
+		}
+		s.app.QueueUpdateDraw(func() {
+			switch {
+			case err != nil:
+				s.setStatus(paint(theme.Error, "내보내지 못했습니다: "+tview.Escape(err.Error())))
+			case articleErr != nil:
+				s.setStatus("내보냈습니다 (기사 본문 제외): " + tview.Escape(path))
+			default:
+				s.setStatus("내보냈습니다: " + tview.Escape(path))
+			}
+		})
+	}()
+}
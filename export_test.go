@@ -0,0 +1,137 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExportFilename(t *testing.T) {
+	content := &TopicContent{Title: "Go 1.22 출시: range over int & more!", Author: "alice"}
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+
+	name, err := exportFilename("{date}-{id}-{title}.md", content, "26364", now)
+	if err != nil || name != "2026-10-18-26364-go-1-22-출시-range-over-int-more.md" {
+		t.Errorf("Expected the placeholders filled in, got %q, %v", name, err)
+	}
+	if name, err := exportFilename("{author}/{id}.md", content, "1", now); err != nil || name != "alice/1.md" {
+		t.Errorf("Expected alice/1.md, got %q, %v", name, err)
+	}
+	if _, err := exportFilename("{slug}.md", content, "1", now); err == nil || !strings.Contains(err.Error(), "{slug}") {
+		t.Errorf("Expected an unknown placeholder error, got %v", err)
+	}
+	if _, err := exportFilename("{title}", &TopicContent{}, "1", now); err == nil {
+		t.Error("Expected an error for an empty file name")
+	}
+
+	if got := slugify(strings.Repeat("가", 100)); len([]rune(got)) != 60 {
+		t.Errorf("Expected slugs cut at 60 characters, got %d", len([]rune(got)))
+	}
+}
+
+func TestConfigInvalidExportFilename(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.ExportFilename = "{name}.md"
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), "export_filename") {
+		t.Errorf("Expected an export_filename error, got %v", err)
+	}
+}
+
+func TestTopicMarkdown(t *testing.T) {
+	page := &TopicPage{
+		ID: "26364",
+		Content: &TopicContent{
+			Title: "Title", ExternalLink: "https://example.com/post", Body: "Summary",
+			Author: "op", Time: "1시간전", Points: "12",
+		},
+		Comments: []Comment{
+			{Author: "alice", Time: "50분전", Body: "First line\nSecond line", Depth: 0},
+			{Author: "op", Time: "40분전", Body: "Reply", Depth: 1},
+			{Author: "bob", Depth: 0},
+		},
+	}
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.Local)
+
+	want := `# Title
+
+- 원문: <https://example.com/post>
+- GeekNews: <https://news.hada.io/topic?id=26364>
+- 작성: op · 1시간전 · 12P
+- 내보낸 날짜: 2026-10-18
+
+## 요약
+
+Summary
+
+## 기사
+
+Article text
+
+## 댓글 3개
+
+> **alice** (50분전)
+>
+> First line
+> Second line
+
+> > **op** (40분전)
+> >
+> > Reply
+
+> **bob**
+>
+> *삭제됨*
+`
+	if got := topicMarkdown(page, "Article text\n", now); got != want {
+		t.Errorf("Unexpected Markdown:\n%s\nwant:\n%s", got, want)
+	}
+
+	page.Content.ExternalLink = "item?id=26364"
+	got := topicMarkdown(page, "", now)
+	if strings.Contains(got, "원문") || strings.Contains(got, "## 기사") {
+		t.Errorf("Expected no article link or section without an external article:\n%s", got)
+	}
+}
+
+func TestExportTopic(t *testing.T) {
+	dir := t.TempDir()
+	settings.ExportDir = filepath.Join(dir, "notes")
+	defer func() { settings.ExportDir = "" }()
+
+	page := &TopicPage{ID: "7", Content: &TopicContent{Title: "Hello World"}}
+	path, err := exportTopic(page, "")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if filepath.Dir(path) != settings.ExportDir || !strings.HasSuffix(path, "-7-hello-world.md") {
+		t.Errorf("Unexpected export path %s", path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil || !strings.HasPrefix(string(data), "# Hello World\n") {
+		t.Errorf("Expected the Markdown in %s, got %q, %v", path, data, err)
+	}
+}
+
+func TestExportArticleFallsBackToBookmark(t *testing.T) {
+	settings.RetryCount = 0
+	defer func() { settings.RetryCount = defaultConfig().RetryCount }()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close() // Fetching the article fails at once
+
+	store, _ := loadBookmarks(filepath.Join(t.TempDir(), "bookmarks.json"))
+	store.add(Bookmark{TopicID: "7", Title: "저장한 글", SavedAt: time.Now()})
+	store.saveCopy("7", "", "저장한 기사 본문")
+	page := &TopicPage{ID: "7", Content: &TopicContent{Title: "저장한 글", ExternalLink: closed.URL + "/article"}}
+
+	article, err := exportArticle(page, store.copyOf("7"))
+	if err != nil || article != "저장한 기사 본문" {
+		t.Errorf("Expected the bookmarked article text, got %q, %v", article, err)
+	}
+	if _, err := exportArticle(page, store.copyOf("8")); err == nil {
+		t.Error("Expected an error without a bookmarked copy")
+	}
+}
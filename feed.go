@@ -0,0 +1,302 @@
+package main
+
+import (
+	"encoding/xml"
+	"flag"
+	"fmt"
+	"html"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FullFeed is an Atom feed of GeekNews topics carrying everything the official feed leaves
+// out: the topic summary, the extracted article, the external link, points and comments
+type FullFeed struct {
+	XMLName   xml.Name        `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string          `xml:"title"`
+	ID        string          `xml:"id"`
+	Updated   string          `xml:"updated"`
+	Links     []AtomLink      `xml:"link"`
+	Generator string          `xml:"generator"`
+	Entries   []FullFeedEntry `xml:"entry"`
+}
+
+// FullFeedEntry is a topic of a FullFeed
+type FullFeedEntry struct {
+	Title     string          `xml:"title"`
+	ID        string          `xml:"id"` // GeekNews topic URL
+	Links     []AtomLink      `xml:"link"`
+	Published string          `xml:"published,omitempty"`
+	Updated   string          `xml:"updated"`
+	Author    FullFeedAuthor  `xml:"author"`
+	Content   FullFeedContent `xml:"content"`
+}
+
+// FullFeedAuthor is the GeekNews user who posted an entry
+type FullFeedAuthor struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri,omitempty"`
+}
+
+// FullFeedContent is the HTML content of an entry
+type FullFeedContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// feedSectionTitles are the titles of the feeds of the sections
+var feedSectionTitles = map[string]string{
+	"top":  "GeekNews",
+	"new":  "GeekNews - 최신글",
+	"ask":  "GeekNews - Ask",
+	"show": "GeekNews - Show",
+}
+
+// buildFullFeed fetches the topics of a section, their pages and, if withArticle, their
+// external articles, and returns them as a feed. A topic whose page cannot be fetched keeps
+// what the list knows of it; an article that cannot be extracted is left out.
+func buildFullFeed(section string, limit int, withArticle bool, fetch func(string) (string, time.Time, error)) (*FullFeed, error) {
+	var fetched time.Time
+	articles, err := fetchSection(section, func(url string) (string, error) {
+		data, at, err := fetch(url)
+		if err == nil && (fetched.IsZero() || at.Before(fetched)) {
+			fetched = at
+		}
+		return data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(articles) > limit {
+		articles = articles[:limit]
+	}
+
+	feed := &FullFeed{
+		Title:     feedSectionTitles[section],
+		ID:        geekNewsBaseURL + listSections[section],
+		Links:     []AtomLink{{Rel: "alternate", Type: "text/html", Href: geekNewsBaseURL + listSections[section]}},
+		Generator: "gn-text " + version,
+	}
+	feed.Entries = fullFeedEntries(articles, withArticle, fetched, fetch)
+	updated := lastUpdated(feed.Entries)
+	if updated.IsZero() {
+		updated = fetched
+	}
+	feed.Updated = formatJSONTime(updated)
+	return feed, nil
+}
+
+// lastUpdated returns the latest update time of entries, or the zero time
+func lastUpdated(entries []FullFeedEntry) time.Time {
+	var updated time.Time
+	for _, entry := range entries {
+		if t, err := time.Parse(time.RFC3339, entry.Updated); err == nil && t.After(updated) {
+			updated = t
+		}
+	}
+	return updated
+}
+
+// first returns the feed with its first n entries only
+func (f *FullFeed) first(n int) *FullFeed {
+	if len(f.Entries) <= n {
+		return f
+	}
+	short := *f
+	short.Entries = f.Entries[:n]
+	if updated := lastUpdated(short.Entries); !updated.IsZero() {
+		short.Updated = formatJSONTime(updated)
+	}
+	return &short
+}
+
+// feedWorkers bounds how many entries of a feed are completed at once
+const feedWorkers = 4
+
+// fullFeedEntries returns the entries of articles in order, completing feedWorkers of them at a time
+func fullFeedEntries(articles []Article, withArticle bool, fetched time.Time, fetch func(string) (string, time.Time, error)) []FullFeedEntry {
+	entries := make([]FullFeedEntry, len(articles))
+	slots := make(chan struct{}, feedWorkers)
+	var wg sync.WaitGroup
+	for i, article := range articles {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, article Article) {
+			defer wg.Done()
+			entries[i] = fullFeedEntry(article, withArticle, fetched, fetch)
+			<-slots
+		}(i, article)
+	}
+	wg.Wait()
+	return entries
+}
+
+// fullFeedEntry returns the entry of an article, completed from its topic page
+func fullFeedEntry(article Article, withArticle bool, fetched time.Time, fetch func(string) (string, time.Time, error)) FullFeedEntry {
+	topicID := extractTopicID(article.CommentsLink)
+	link := listField(article, "link")
+	summary := article.Summary
+	points, comments := article.Points, article.Comments
+	posted := article.Posted
+	author, authorID := article.Author, article.AuthorID
+
+	var text string
+	if data, at, err := fetch(geekNewsBaseURL + "topic?id=" + topicID); err == nil {
+		if page, err := parseTopicPage(topicID, data); err == nil && page.Content.Title != "" {
+			content := page.Content
+			if strings.HasPrefix(content.ExternalLink, "http") {
+				link = content.ExternalLink
+			}
+			summary = strings.TrimSpace(content.Body)
+			points, comments = content.Points, fmt.Sprint(len(page.Comments))
+			if authorID == "" {
+				author, authorID = content.Author, content.AuthorID
+			}
+			if posted.IsZero() {
+				posted = content.Posted
+			}
+			if posted.IsZero() {
+				posted, _ = relativeTime(content.Time, at)
+			}
+		}
+	}
+	if withArticle && link != "" {
+		if data, _, err := fetch(link); err == nil {
+			text, _ = parseArticleText(data)
+		}
+	}
+
+	entry := FullFeedEntry{
+		Title:  article.Title,
+		ID:     article.CommentsLink,
+		Author: FullFeedAuthor{Name: author},
+		Content: FullFeedContent{
+			Type: "html",
+			Body: fullFeedHTML(article, link, summary, points, comments, text),
+		},
+	}
+	if link != "" {
+		entry.Links = append(entry.Links, AtomLink{Rel: "alternate", Type: "text/html", Href: link})
+	}
+	entry.Links = append(entry.Links, AtomLink{Rel: "replies", Type: "text/html", Href: article.CommentsLink})
+	if authorID != "" {
+		entry.Author.URI = geekNewsBaseURL + "user?id=" + url.QueryEscape(authorID)
+	}
+	if entry.Author.Name == "" {
+		entry.Author.Name = "GeekNews" // Atom requires a name
+	}
+	if posted.IsZero() {
+		entry.Updated = formatJSONTime(fetched)
+	} else {
+		entry.Published = formatJSONTime(posted)
+		entry.Updated = entry.Published
+	}
+	return entry
+}
+
+// fullFeedHTML returns the HTML content of an entry: points, comments and links, then the
+// summary and the article text
+func fullFeedHTML(article Article, link, summary, points, comments, text string) string {
+	var b strings.Builder
+	var meta []string
+	if points != "" {
+		meta = append(meta, "포인트 "+html.EscapeString(points))
+	}
+	if comments != "" {
+		meta = append(meta, fmt.Sprintf(`<a href="%s">댓글 %s개</a>`, html.EscapeString(article.CommentsLink), html.EscapeString(comments)))
+	} else {
+		meta = append(meta, fmt.Sprintf(`<a href="%s">GeekNews</a>`, html.EscapeString(article.CommentsLink)))
+	}
+	if link != "" {
+		meta = append(meta, fmt.Sprintf(`원문: <a href="%s">%s</a>`, html.EscapeString(link), html.EscapeString(extractDomainFromURL(link))))
+	}
+	fmt.Fprintf(&b, "<p>%s</p>\n", strings.Join(meta, " · "))
+	b.WriteString(textHTML(summary))
+	if text = strings.TrimSpace(text); text != "" {
+		b.WriteString("<hr>\n<h3>기사</h3>\n")
+		b.WriteString(textHTML(text))
+	}
+	return b.String()
+}
+
+// textHTML returns plain text as HTML paragraphs, one per block separated by blank lines
+func textHTML(text string) string {
+	var b strings.Builder
+	for _, paragraph := range paragraphs(text) {
+		lines := strings.Split(html.EscapeString(paragraph), "\n")
+		fmt.Fprintf(&b, "<p>%s</p>\n", strings.Join(lines, "<br>\n"))
+	}
+	return b.String()
+}
+
+// writeFullFeed writes a feed as indented XML
+func writeFullFeed(w io.Writer, feed *FullFeed) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(feed); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+// writeFullFeedFile writes a feed to path through a temporary file, so that a feed reader
+// or web server never sees it half-written
+func writeFullFeedFile(path string, feed *FullFeed) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".gn-text-feed-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeFullFeed(tmp, feed); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o644); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// runFeed implements `gn-text feed`, which writes the full-content Atom feed of a section
+func runFeed(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("feed", flag.ContinueOnError)
+	fs.SetOutput(w)
+	output := fs.String("o", "-", "Write the feed to this file (- for stdout)")
+	section := fs.String("section", "top", "Section: top, new, ask or show")
+	limit := fs.Int("limit", 20, "Include at most this many topics (0 includes all)")
+	noArticle := fs.Bool("no-article", false, "Leave out the extracted article texts")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if _, ok := listSections[*section]; !ok {
+		fmt.Fprintf(os.Stderr, "gn-text: unknown section %q (available: top, new, ask, show)\n", *section)
+		return 2
+	}
+
+	feed, err := buildFullFeed(*section, *limit, !*noArticle, fetchCachedTime)
+	if err == nil {
+		if *output == "-" {
+			err = writeFullFeed(w, feed)
+		} else {
+			err = writeFullFeedFile(*output, feed)
+		}
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildFullFeed(t *testing.T) {
+	feed, err := buildFullFeed("top", 0, true, testdataFetchTime(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(feed.Entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(feed.Entries))
+	}
+
+	articles, _ := fetchSection("top", testdataFetch(t, true))
+	for i, entry := range feed.Entries {
+		if entry.ID != articles[i].CommentsLink {
+			t.Errorf("Expected entry %d to be %s, got %s", i, articles[i].CommentsLink, entry.ID)
+		}
+	}
+
+	var full, fallback *FullFeedEntry
+	for i := range feed.Entries {
+		switch extractTopicID(feed.Entries[i].ID) {
+		case "26364":
+			full = &feed.Entries[i]
+		case "26363":
+			fallback = &feed.Entries[i]
+		}
+	}
+	if full == nil || fallback == nil {
+		t.Fatalf("Expected entries for topics 26364 and 26363, got %+v", feed.Entries)
+	}
+
+	if full.Links[0].Rel != "alternate" || full.Links[0].Href != "https://www.anthropic.com/research/AI-assistance-coding-skills" {
+		t.Errorf("Expected the external article as the alternate link, got %+v", full.Links)
+	}
+	for _, want := range []string{"포인트 ", "댓글 ", "원문: ", "<h3>기사</h3>", "<p>Extracted article text."} {
+		if !strings.Contains(full.Content.Body, want) {
+			t.Errorf("Expected %q in the content, got:\n%s", want, full.Content.Body)
+		}
+	}
+
+	// The topic page of 26363 cannot be fetched: its summary comes from the official feed
+	if fallback.Content.Body == "" || strings.Contains(fallback.Content.Body, "<h3>기사</h3>") {
+		t.Errorf("Expected the feed summary without an article, got:\n%s", fallback.Content.Body)
+	}
+	if fallback.Published == "" {
+		t.Error("Expected the publication time from the official feed")
+	}
+}
+
+func TestWriteFullFeedParses(t *testing.T) {
+	feed, err := buildFullFeed("top", 1, false, testdataFetchTime(t))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := writeFullFeed(&out, feed); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	// Readers of the official feed read this one too, linking to the external article
+	articles, err := parseGeekNewsRSS(out.String())
+	if err != nil {
+		t.Fatalf("Written feed does not parse: %v\n%s", err, out.String())
+	}
+	if len(articles) != 1 || articles[0].Title != feed.Entries[0].Title || articles[0].CommentsLink != feed.Entries[0].Links[0].Href {
+		t.Errorf("Unexpected articles read back: %+v", articles)
+	}
+}
+
+func TestTextHTML(t *testing.T) {
+	got := textHTML("첫 문단 <b>\n둘째 줄\n\n\n다음 & 문단\n")
+	want := "<p>첫 문단 &lt;b&gt;<br>\n둘째 줄</p>\n<p>다음 &amp; 문단</p>\n"
+	if got != want {
+		t.Errorf("textHTML = %q, want %q", got, want)
+	}
+}
+
+func TestFullFeedEntryAuthor(t *testing.T) {
+	offline := func(string) (string, time.Time, error) { return "", time.Time{}, errors.New("offline") }
+	entry := fullFeedEntry(Article{Title: "제목", CommentsLink: geekNewsBaseURL + "topic?id=7", Author: "GN⁺", AuthorID: "a b&c"}, false, goldenTime, offline)
+	if entry.Author.Name != "GN⁺" || entry.Author.URI != geekNewsBaseURL+"user?id=a+b%26c" {
+		t.Errorf("Expected the shown name and the escaped account ID in the URI, got %+v", entry.Author)
+	}
+
+	// Atom needs a name even when the author is unknown
+	entry = fullFeedEntry(Article{Title: "제목", CommentsLink: geekNewsBaseURL + "topic?id=7"}, false, goldenTime, offline)
+	if entry.Author.Name == "" || entry.Author.URI != "" {
+		t.Errorf("Expected a fallback author name without a URI, got %+v", entry.Author)
+	}
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// listFilter narrows the article list to titles matching the typed query
+type listFilter struct {
+	input    *tview.InputField
+	original int // Selection to restore when the filter is cleared
+	active   bool
+}
+
+func (f *listFilter) isActive() bool {
+	return f != nil && f.active
+}
+
+// startFilter shows the filter input below the article list and focuses it
+func (s *session) startFilter() {
+	if s.filter == nil {
+		s.filter = &listFilter{}
+		s.filter.input = tview.NewInputField().SetLabel("/")
+		s.filter.input.SetChangedFunc(s.applyFilter)
+		s.filter.input.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEscape {
+				s.clearFilter()
+				return
+			}
+			// Keep the filtered list and go back to it
+			s.app.SetFocus(s.list)
+		})
+	}
+
+	if !s.filter.active {
+		s.filter.active = true
+		s.filter.original = s.list.GetCurrentItem()
+		s.home.AddItem(s.filter.input, 1, 0, false)
+	}
+	s.app.SetFocus(s.filter.input)
+}
+
+// applyFilter rebuilds the list with the articles matching query
+func (s *session) applyFilter(query string) {
+	s.fillList()
+}
+
+// clearFilter restores the full list and the selection from before filtering
+func (s *session) clearFilter() {
+	if !s.filter.isActive() {
+		return
+	}
+	s.filter.active = false
+	s.filter.input.SetText("")
+	s.home.RemoveItem(s.filter.input)
+
+	s.fillList()
+	s.list.SetCurrentItem(s.filter.original)
+	s.app.SetFocus(s.list)
+}
+
+// listArticle returns the article shown at row index of the article list
+func (s *session) listArticle(index int) (Article, bool) {
+	if index < 0 || index >= len(s.rows) {
+		return Article{}, false
+	}
+	return s.articles[s.rows[index]], true
+}
+
+// currentIndex returns the index into articles of the selected list row, or -1
+func (s *session) currentIndex() int {
+	row := s.list.GetCurrentItem()
+	if row < 0 || row >= len(s.rows) {
+		return -1
+	}
+	return s.rows[row]
+}
+
+// selectIndex selects the list row of articles[index] or, if it is not shown, the next one
+func (s *session) selectIndex(index int) {
+	for row, i := range s.rows {
+		if i >= index {
+			s.list.SetCurrentItem(row)
+			return
+		}
+	}
+	s.list.SetCurrentItem(len(s.rows) - 1)
+}
+
+// matchesFilter reports whether every word of query matches the article title or domain
+func matchesFilter(article Article, query string) bool {
+	for _, word := range strings.Fields(query) {
+		if !fuzzyContains(article.Title, word) && !fuzzyContains(article.Domain, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// fuzzyContains reports whether text contains query, ignoring case. A Hangul initial
+// consonant (초성) in query matches any syllable starting with it, so "ㅇㅍ" matches "오픈".
+func fuzzyContains(text, query string) bool {
+	t := []rune(strings.ToLower(text))
+	q := []rune(strings.ToLower(query))
+	if len(q) == 0 {
+		return true
+	}
+
+	for start := 0; start+len(q) <= len(t); start++ {
+		matched := true
+		for i, r := range q {
+			if !runeMatches(r, t[start+i]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// runeMatches reports whether a query rune matches a text rune. Initial consonants only
+// match Hangul syllables; Latin text has to be typed as it is.
+func runeMatches(query, text rune) bool {
+	if query == text {
+		return true
+	}
+	return isChoseong(query) && choseongOf(text) == query
+}
+
+// choseongs are the Hangul compatibility jamo for the initial consonants, in syllable order
+var choseongs = []rune("ㄱㄲㄴㄷㄸㄹㅁㅂㅃㅅㅆㅇㅈㅉㅊㅋㅌㅍㅎ")
+
+func isChoseong(r rune) bool {
+	for _, c := range choseongs {
+		if r == c {
+			return true
+		}
+	}
+	return false
+}
+
+// choseongOf returns the initial consonant of a Hangul syllable, or 0
+func choseongOf(r rune) rune {
+	if r >= 0xAC00 && r <= 0xD7A3 {
+		return choseongs[(r-0xAC00)/588]
+	}
+	return 0
+}
@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func TestFuzzyContains(t *testing.T) {
+	tests := []struct {
+		text     string
+		query    string
+		expected bool
+	}{
+		{"오픈AI, 새 모델 공개", "ㅇㅍa", true},
+		{"오픈AI, 새 모델 공개", "ㅇㅍㅇ", false},
+		{"오픈AI, 새 모델 공개", "ㅇㅍ", true},
+		{"오픈AI, 새 모델 공개", "오픈ai", true},
+		{"오픈AI, 새 모델 공개", "ㅁㄷ", true},
+		{"오픈AI, 새 모델 공개", "ㄱㄱ", true},
+		{"오픈AI, 새 모델 공개", "ㅎㅎ", false},
+		{"Claude Code 창시자가 공개한 실전 사용 팁", "claude", true},
+		{"Claude Code 창시자가 공개한 실전 사용 팁", "CODE", true},
+		{"Claude Code 창시자가 공개한 실전 사용 팁", "ㅊㅅㅈ", true},
+		{"Claude Code 창시자가 공개한 실전 사용 팁", "ㅊㅅ자", true},
+		{"Claude Code 창시자가 공개한 실전 사용 팁", "ㅉ", false},
+		{"GPT-5 출시", "ㅈㅍㅌ", false},
+		{"GPT-5 출시", "gptㅊ", false},
+		{"GPT-5 출시", "gpt-5 ㅊㅅ", true},
+		{"Hello world", "ㅇㅇ", false},
+		{"Good job zone", "ㅈ", false},
+		{"AI agents in Rust", "ㅇㅇ", false},
+		{"새 AI 모델", "ㅇ", false},
+		{"Go 1.26 릴리스", "", true},
+		{"", "go", false},
+	}
+
+	for _, test := range tests {
+		result := fuzzyContains(test.text, test.query)
+		if result != test.expected {
+			t.Errorf("fuzzyContains(%q, %q) = %v, expected %v", test.text, test.query, result, test.expected)
+		}
+	}
+}
+
+func TestMatchesFilter(t *testing.T) {
+	article := Article{
+		Title:  "Claude Skills 구축을 위한 완벽 가이드",
+		Domain: "claude.com",
+	}
+
+	tests := []struct {
+		query    string
+		expected bool
+	}{
+		{"skills", true},
+		{"claude.com", true},
+		{"ㄱㅇㄷ skills", true},
+		{"skills github.com", false},
+		{"  ", true},
+	}
+
+	for _, test := range tests {
+		result := matchesFilter(article, test.query)
+		if result != test.expected {
+			t.Errorf("matchesFilter(%q) = %v, expected %v", test.query, result, test.expected)
+		}
+	}
+}
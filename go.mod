@@ -9,6 +9,7 @@ require (
 	github.com/mattn/go-runewidth v0.0.15
 	github.com/rivo/tview v0.0.0-20240524063012-037df494fb76
 	golang.org/x/term v0.19.0
+	gopkg.in/yaml.v3 v3.0.1
 	jaytaylor.com/html2text v0.0.0-20230321000545-74c2419ad056
 )
 
@@ -0,0 +1,345 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// maxHistoryEntries is the number of views kept in the reading history
+const maxHistoryEntries = 1000
+
+// HistoryEntry is a view of the comments or article of a topic
+type HistoryEntry struct {
+	TopicID  string    `json:"topic_id"`
+	Title    string    `json:"title"`
+	TopicURL string    `json:"topic_url"`
+	Link     string    `json:"link,omitempty"` // External article URL
+	Domain   string    `json:"domain,omitempty"`
+	View     string    `json:"view"` // "comments" or "article"
+	ViewedAt time.Time `json:"viewed_at"`
+}
+
+// article returns the topic of the entry as a list article
+func (e HistoryEntry) article() Article {
+	return Article{Title: e.Title, Link: e.Link, CommentsLink: e.TopicURL, Domain: e.Domain}
+}
+
+// scrollPosition is where a text page was left: a block of its layout and the line within
+// it, so that it can be restored whatever the width the page is laid out for
+type scrollPosition struct {
+	Block  int `json:"block"`
+	Offset int `json:"offset"`
+}
+
+// historyStore is the reading history with the last scroll position of each page read
+type historyStore struct {
+	Entries   []HistoryEntry            `json:"entries"`   // Oldest first
+	Positions map[string]scrollPosition `json:"positions"` // By view and topic ID, e.g. "article:1234"
+
+	path string // File the history is saved to; not saved if empty
+}
+
+// history is the reading history, set up by main. Without it nothing is recorded.
+var history *historyStore
+
+// historyPath returns the path of the history file
+func historyPath() (string, error) {
+	return statePath("history.json")
+}
+
+func newHistory(path string) *historyStore {
+	return &historyStore{Positions: make(map[string]scrollPosition), path: path}
+}
+
+// loadHistory reads the history saved at path
+func loadHistory(path string) (*historyStore, error) {
+	h := newHistory(path)
+	if err := loadState(path, h); err != nil {
+		return nil, err
+	}
+	if h.Positions == nil {
+		h.Positions = make(map[string]scrollPosition)
+	}
+	return h, nil
+}
+
+// record adds a view to the history. Viewing the same page again right away only
+// updates the time of the last entry.
+func (h *historyStore) record(view string, article Article) error {
+	topicID := extractTopicID(article.CommentsLink)
+	if h == nil || topicID == "" {
+		return nil
+	}
+
+	entry := HistoryEntry{
+		TopicID:  topicID,
+		Title:    article.Title,
+		TopicURL: article.CommentsLink,
+		Link:     article.Link,
+		Domain:   article.Domain,
+		View:     view,
+		ViewedAt: time.Now(),
+	}
+	if last := len(h.Entries) - 1; last >= 0 && h.Entries[last].TopicID == topicID && h.Entries[last].View == view {
+		h.Entries[last] = entry
+	} else {
+		h.Entries = append(h.Entries, entry)
+	}
+	if len(h.Entries) > maxHistoryEntries {
+		h.Entries = h.Entries[len(h.Entries)-maxHistoryEntries:]
+		h.forgetPositions()
+	}
+	return h.save()
+}
+
+// forgetPositions drops the scroll positions of topics no longer in the history
+func (h *historyStore) forgetPositions() {
+	kept := make(map[string]bool)
+	for _, e := range h.Entries {
+		kept[positionKey(e.View, e.TopicID)] = true
+	}
+	for key := range h.Positions {
+		if !kept[key] {
+			delete(h.Positions, key)
+		}
+	}
+}
+
+func positionKey(view, topicID string) string {
+	return view + ":" + topicID
+}
+
+// position returns where the view of the topic was left, if it was read before
+func (h *historyStore) position(view, topicID string) (scrollPosition, bool) {
+	if h == nil {
+		return scrollPosition{}, false
+	}
+	pos, ok := h.Positions[positionKey(view, topicID)]
+	return pos, ok
+}
+
+// setPosition remembers where the view of the topic was left
+func (h *historyStore) setPosition(view, topicID string, pos scrollPosition) error {
+	if h == nil {
+		return nil
+	}
+	key := positionKey(view, topicID)
+	if old, ok := h.Positions[key]; ok && old == pos {
+		return nil
+	}
+	h.Positions[key] = pos
+	return h.save()
+}
+
+func (h *historyStore) save() error {
+	if h.path == "" {
+		return nil
+	}
+	return saveState(h.path, h)
+}
+
+// historyDay is the history of one day, newest first
+type historyDay struct {
+	Date    time.Time // Midnight, local time
+	Entries []HistoryEntry
+}
+
+// historyDays groups the entries matching query by local day, newest first. Each page
+// is listed once a day, at the last time it was viewed.
+func historyDays(entries []HistoryEntry, query string) []historyDay {
+	var days []historyDay
+	seen := make(map[string]bool)
+	for i := len(entries) - 1; i >= 0; i-- {
+		e := entries[i]
+		if !matchesFilter(e.article(), query) {
+			continue
+		}
+		t := e.ViewedAt.Local()
+		date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.Local)
+		if len(days) == 0 || !days[len(days)-1].Date.Equal(date) {
+			days = append(days, historyDay{Date: date})
+			seen = make(map[string]bool)
+		}
+		key := positionKey(e.View, e.TopicID)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		days[len(days)-1].Entries = append(days[len(days)-1].Entries, e)
+	}
+	return days
+}
+
+var weekdays = []string{"일", "월", "화", "수", "목", "금", "토"}
+
+// dayTitle names a day of the history relative to now
+func dayTitle(date, now time.Time) string {
+	title := fmt.Sprintf("%s (%s)", date.Format("2006-01-02"), weekdays[date.Weekday()])
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	switch {
+	case date.Equal(today):
+		title += " 오늘"
+	case date.Equal(today.AddDate(0, 0, -1)):
+		title += " 어제"
+	}
+	return title
+}
+
+// historyView lists the reading history by day and can be searched with '/'
+type historyView struct {
+	s       *session
+	layout  *tview.Flex
+	list    *tview.List
+	input   *tview.InputField
+	query   string
+	entries []*HistoryEntry // Entry of each list row; nil for day headings
+	row     int             // Last selected row, to skip headings in the direction moved
+}
+
+// openHistory shows the reading history
+func (s *session) openHistory() {
+	if s.historyView == nil {
+		v := &historyView{s: s, list: styleList(tview.NewList().ShowSecondaryText(true))}
+		v.layout = tview.NewFlex().SetDirection(tview.FlexRow).AddItem(v.list, 0, 1, true)
+		v.list.SetChangedFunc(func(row int, _, _ string, _ rune) {
+			v.skipHeading(row)
+		})
+		s.listMouse(v.list)
+		s.historyView = v
+	}
+	s.historyView.fill()
+	s.showPage("history", s.historyView.layout)
+}
+
+// fill lists the history entries matching the search
+func (v *historyView) fill() {
+	v.entries = nil
+	v.row = 0
+	v.list.Clear()
+
+	var entries []HistoryEntry
+	if history != nil {
+		entries = history.Entries
+	}
+	now := time.Now()
+	for _, day := range historyDays(entries, v.query) {
+		v.entries = append(v.entries, nil)
+		v.list.AddItem(paint(theme.Title, dayTitle(day.Date, now)), "", 0, nil)
+		for i := range day.Entries {
+			e := &day.Entries[i]
+			kind := "댓글"
+			if e.View == "article" {
+				kind = "기사"
+			}
+			secondary := e.ViewedAt.Local().Format("15:04") + " · " + kind
+			if e.Domain != "" {
+				secondary += " · " + tview.Escape(e.Domain)
+			}
+			v.entries = append(v.entries, e)
+			v.list.AddItem("  "+tview.Escape(e.Title), "  "+secondary, 0, nil)
+		}
+	}
+
+	switch {
+	case len(v.entries) > 0:
+		v.list.SetCurrentItem(1)
+	case v.query != "":
+		v.list.AddItem("일치하는 기록이 없습니다", "", 0, nil)
+	default:
+		v.list.AddItem("읽은 기록이 없습니다", "", 0, nil)
+	}
+}
+
+// skipHeading moves the selection off a day heading, in the direction it was moving
+func (v *historyView) skipHeading(row int) {
+	if row < 0 || row >= len(v.entries) || v.entries[row] != nil {
+		v.row = row
+		return
+	}
+	next := row + 1
+	if (row < v.row || next >= len(v.entries)) && row > 0 {
+		next = row - 1
+	}
+	v.row = row
+	if next < len(v.entries) {
+		v.list.SetCurrentItem(next)
+	}
+}
+
+// selected returns the highlighted history entry
+func (v *historyView) selected() (*HistoryEntry, bool) {
+	if v == nil {
+		return nil, false
+	}
+	row := v.list.GetCurrentItem()
+	if row < 0 || row >= len(v.entries) || v.entries[row] == nil {
+		return nil, false
+	}
+	return v.entries[row], true
+}
+
+// open opens the comments or article of the highlighted entry
+func (v *historyView) open() {
+	e, ok := v.selected()
+	if !ok {
+		return
+	}
+	article := e.article()
+	if e.View == "article" {
+		v.s.topic = article
+		v.s.page = nil
+		v.s.openArticle(article)
+		return
+	}
+	v.s.openComments(article)
+}
+
+// startSearch shows the search input below the history and focuses it
+func (v *historyView) startSearch() {
+	if v.input == nil {
+		previous := v.query
+		v.input = tview.NewInputField().SetLabel("/").SetText(v.query)
+		v.input.SetChangedFunc(func(query string) {
+			v.query = query
+			v.fill()
+		})
+		v.input.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEscape {
+				v.query = previous
+				v.fill()
+			}
+			v.layout.RemoveItem(v.input)
+			v.input = nil
+			v.s.app.SetFocus(v.list)
+		})
+		v.layout.AddItem(v.input, 1, 0, false)
+	}
+	v.s.app.SetFocus(v.input)
+}
+
+// recordView adds a view of the comments or article of article to the history
+func (s *session) recordView(view string, article Article) {
+	if err := history.record(view, article); err != nil {
+		s.setStatus(paint(theme.Error, "읽은 기록을 저장하지 못했습니다: "+tview.Escape(err.Error())))
+	}
+}
+
+// savePosition remembers where the text page being left was scrolled to
+func (s *session) savePosition() {
+	name := s.frontPage()
+	p := s.textPages[name]
+	if p == nil || p.topicID == "" || len(p.anchors) == 0 {
+		return
+	}
+	row, _ := p.view.GetScrollOffset()
+	block, offset := anchorAt(p.anchors, row)
+	if block < 0 {
+		block, offset = 0, 0
+	}
+	if err := history.setPosition(name, p.topicID, scrollPosition{Block: block, Offset: offset}); err != nil {
+		s.setStatus(paint(theme.Error, "읽던 위치를 저장하지 못했습니다: "+tview.Escape(err.Error())))
+	}
+}
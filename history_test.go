@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func topicArticle(id, title string) Article {
+	return Article{Title: title, CommentsLink: geekNewsBaseURL + "topic?id=" + id, Domain: "example.com"}
+}
+
+func TestHistoryRecord(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.json")
+	h, err := loadHistory(path)
+	if err != nil {
+		t.Fatalf("Unexpected error for a missing history: %v", err)
+	}
+
+	h.record("comments", topicArticle("1", "첫 글"))
+	h.record("comments", topicArticle("1", "첫 글")) // Same page again: only the time changes
+	h.record("article", topicArticle("1", "첫 글"))
+	h.record("comments", topicArticle("2", "둘째 글"))
+	h.record("comments", Article{Title: "토픽 아님"})
+	if err := h.setPosition("article", "1", scrollPosition{Block: 3, Offset: 2}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	h, err = loadHistory(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(h.Entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %+v", h.Entries)
+	}
+	if pos, ok := h.position("article", "1"); !ok || pos != (scrollPosition{Block: 3, Offset: 2}) {
+		t.Errorf("Expected the saved position, got %+v, %v", pos, ok)
+	}
+	if _, ok := h.position("comments", "1"); ok {
+		t.Error("Expected no position for a page never left")
+	}
+}
+
+func TestHistoryTrim(t *testing.T) {
+	h := newHistory("")
+	h.record("comments", topicArticle("0", "오래된 글"))
+	h.setPosition("comments", "0", scrollPosition{Block: 1})
+	for i := 1; i <= maxHistoryEntries; i++ {
+		h.record("comments", topicArticle(fmt.Sprint(i), "글"))
+	}
+	if len(h.Entries) > maxHistoryEntries {
+		t.Errorf("Expected at most %d entries, got %d", maxHistoryEntries, len(h.Entries))
+	}
+	if _, ok := h.position("comments", "0"); ok {
+		t.Error("Expected the position of a forgotten topic to be dropped")
+	}
+}
+
+func TestHistoryDays(t *testing.T) {
+	day := func(d, hour int) time.Time { return time.Date(2026, 10, d, hour, 0, 0, 0, time.Local) }
+	entries := []HistoryEntry{
+		{TopicID: "1", Title: "Go 릴리스", View: "comments", ViewedAt: day(16, 9)},
+		{TopicID: "2", Title: "러스트 소식", View: "comments", ViewedAt: day(17, 9)},
+		{TopicID: "2", Title: "러스트 소식", View: "article", ViewedAt: day(17, 10)},
+		{TopicID: "1", Title: "Go 릴리스", View: "comments", ViewedAt: day(18, 8)},
+		{TopicID: "3", Title: "Go 팁", View: "comments", ViewedAt: day(18, 9)},
+		{TopicID: "1", Title: "Go 릴리스", View: "comments", ViewedAt: day(18, 10)},
+	}
+
+	days := historyDays(entries, "")
+	if len(days) != 3 || !days[0].Date.Equal(day(18, 0)) || !days[2].Date.Equal(day(16, 0)) {
+		t.Fatalf("Expected 3 days newest first, got %+v", days)
+	}
+	if len(days[0].Entries) != 2 || days[0].Entries[0].TopicID != "1" || !days[0].Entries[0].ViewedAt.Equal(day(18, 10)) {
+		t.Errorf("Expected each page once a day at its last view, got %+v", days[0].Entries)
+	}
+	if len(days[1].Entries) != 2 {
+		t.Errorf("Expected the comments and article of a topic listed apart, got %+v", days[1].Entries)
+	}
+
+	days = historyDays(entries, "go")
+	if len(days) != 2 || len(days[0].Entries) != 2 || len(days[1].Entries) != 1 {
+		t.Errorf("Expected only matching entries, got %+v", days)
+	}
+
+	now := day(18, 12)
+	for date, expected := range map[time.Time]string{
+		day(18, 0): "2026-10-18 (일) 오늘",
+		day(17, 0): "2026-10-17 (토) 어제",
+		day(16, 0): "2026-10-16 (금)",
+	} {
+		if got := dayTitle(date, now); got != expected {
+			t.Errorf("dayTitle(%v) = %q, expected %q", date, got, expected)
+		}
+	}
+}
+
+func TestTextPageResume(t *testing.T) {
+	text := strings.Repeat("긴 문단입니다. ", 40) + "\n\n" + strings.Repeat("둘째 문단입니다. ", 40) + "\n\n" + strings.Repeat("셋째 문단. ", 40)
+	p := newTextPage(func(width int) ([]string, []int) { return layoutText(text, width) })
+	p.view.SetRect(0, 0, 40, 10)
+	p.resume = &scrollPosition{Block: 2, Offset: 1}
+	p.reflow(40)
+
+	row, _ := p.view.GetScrollOffset()
+	if row != p.anchors[2]+1 {
+		t.Errorf("Expected to resume at line %d, got %d", p.anchors[2]+1, row)
+	}
+	if p.resume != nil {
+		t.Error("Expected the resume position to be used once")
+	}
+}
@@ -0,0 +1,560 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// action is a key binding: the keys that trigger it, where it applies and what it does
+type action struct {
+	Name        string   // Stable identifier
+	Keys        []string // Key sequences: key names as returned by keyName, separated by spaces ("j", "Ctrl+C", "g g")
+	Views       []string // Pages the action is available on; empty means every page
+	Description string
+
+	// Run handles the key. It returns nil when the key was consumed, or the event
+	// to pass on to the focused widget (possibly a different key).
+	Run func(s *session, event *tcell.EventKey) *tcell.EventKey
+}
+
+// viewTitles are the page names shown in help, in display order
+var viewTitles = []struct{ name, title string }{
+	{"homepage", "List"},
+	{"comments", "Comments"},
+	{"article", "Article"},
+	{"user", "User"},
+	{"authors", "Author picker"},
+	{"saved", "Saved"},
+	{"history", "History"},
+}
+
+// defaultKeymap returns every key binding, in the order shown in help and the README.
+// The first action bound to a key in the current view handles it.
+func defaultKeymap() []action {
+	return []action{
+		{Name: "quit", Keys: []string{"q", "Ctrl+C"}, Description: "Quit", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.savePosition()
+			s.app.Stop()
+			return nil
+		}},
+		{Name: "down", Keys: []string{"j", "Down"}, Description: "Move down", Run: func(*session, *tcell.EventKey) *tcell.EventKey {
+			return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
+		}},
+		{Name: "up", Keys: []string{"k", "Up"}, Description: "Move up", Run: func(*session, *tcell.EventKey) *tcell.EventKey {
+			return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
+		}},
+		{Name: "top", Keys: []string{"g g", "Home"}, Description: "Go to top", Run: func(*session, *tcell.EventKey) *tcell.EventKey {
+			return tcell.NewEventKey(tcell.KeyHome, 0, tcell.ModNone)
+		}},
+		{Name: "bottom", Keys: []string{"G", "End"}, Description: "Go to bottom", Run: func(*session, *tcell.EventKey) *tcell.EventKey {
+			return tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone)
+		}},
+		{Name: "open", Keys: []string{"l", "Right"}, Description: "View comments / article", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.nextPage()
+			return nil
+		}},
+		{Name: "back", Keys: []string{"h", "Left"}, Description: "Go back", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.backPage()
+			return nil
+		}},
+		{Name: "forward", Keys: []string{"L"}, Description: "Go forward", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.forwardPage()
+			return nil
+		}},
+		{Name: "open-article-browser", Keys: []string{"Space"}, Description: "Open article in browser", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			if article, ok := s.selectedArticle(); ok {
+				openArticleInBrowser(article)
+				s.markRead(article)
+			}
+			return nil
+		}},
+		{Name: "open-comments-browser", Keys: []string{"c"}, Description: "Open comments in browser", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			if article, ok := s.selectedArticle(); ok {
+				openCommentsInBrowser(article)
+			}
+			return nil
+		}},
+		{Name: "author", Keys: []string{"u"}, Description: "View author profile", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.openAuthor()
+			return nil
+		}},
+		{Name: "bookmark", Keys: []string{"b"}, Description: "Bookmark or unbookmark the topic", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.toggleBookmark()
+			return nil
+		}},
+		{Name: "export", Keys: []string{"e"}, Description: "Export the topic to Markdown", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.exportSelected()
+			return nil
+		}},
+		{Name: "saved", Keys: []string{"B"}, Description: "View bookmarks", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.openSaved()
+			return nil
+		}},
+		{Name: "history", Keys: []string{"H"}, Description: "View reading history", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.openHistory()
+			return nil
+		}},
+		{Name: "refresh", Keys: []string{"r"}, Description: "Refresh", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.refresh()
+			return nil
+		}},
+		{Name: "preview", Keys: []string{"p"}, Views: []string{"homepage"}, Description: "Toggle the preview pane", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.togglePreview()
+			return nil
+		}},
+		{Name: "hide-read", Keys: []string{"x"}, Views: []string{"homepage"}, Description: "Hide or show read articles", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.toggleHideRead()
+			return nil
+		}},
+		{Name: "mark-all-read", Keys: []string{"M"}, Views: []string{"homepage"}, Description: "Mark all as read", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.markAllRead()
+			return nil
+		}},
+		{Name: "filter", Keys: []string{"/"}, Views: []string{"homepage"}, Description: "Filter the article list", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.startFilter()
+			return nil
+		}},
+		{Name: "clear-filter", Keys: []string{"Esc"}, Views: []string{"homepage"}, Description: "Clear the filter", Run: func(s *session, event *tcell.EventKey) *tcell.EventKey {
+			if !s.filter.isActive() {
+				return event
+			}
+			s.clearFilter()
+			return nil
+		}},
+		{Name: "remove-bookmark", Keys: []string{"d"}, Views: []string{"saved"}, Description: "Remove the bookmark", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.toggleBookmark()
+			return nil
+		}},
+		{Name: "sort-saved", Keys: []string{"s"}, Views: []string{"saved"}, Description: "Sort by title or saved time", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.toggleSavedSort()
+			return nil
+		}},
+		{Name: "search-history", Keys: []string{"/"}, Views: []string{"history"}, Description: "Search the history", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.historyView.startSearch()
+			return nil
+		}},
+		{Name: "search", Keys: []string{"/"}, Views: []string{"comments", "article"}, Description: "Search in view", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.startSearch()
+			return nil
+		}},
+		{Name: "expand", Keys: []string{"z"}, Views: []string{"comments"}, Description: "Expand folded replies", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.expandFolds(-1)
+			return nil
+		}},
+		{Name: "next-new-comment", Keys: []string{"]"}, Views: []string{"comments"}, Description: "Jump to the next new comment", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.nextNewComment()
+			return nil
+		}},
+		{Name: "next-match", Keys: []string{"n"}, Views: []string{"comments", "article"}, Description: "Next search match", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.nextMatch(1)
+			return nil
+		}},
+		{Name: "previous-match", Keys: []string{"N"}, Views: []string{"comments", "article"}, Description: "Previous search match", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.nextMatch(-1)
+			return nil
+		}},
+		{Name: "help", Keys: []string{"?"}, Description: "Show key help", Run: func(s *session, _ *tcell.EventKey) *tcell.EventKey {
+			s.toggleHelp()
+			return nil
+		}},
+	}
+}
+
+// keyName returns the name of a key event as used in the keymap
+func keyName(event *tcell.EventKey) string {
+	var mods []string
+	if event.Modifiers()&tcell.ModCtrl != 0 {
+		mods = append(mods, "Ctrl")
+	}
+	if event.Modifiers()&tcell.ModAlt != 0 {
+		mods = append(mods, "Alt")
+	}
+
+	var name string
+	switch {
+	case event.Key() == tcell.KeyRune && event.Rune() == ' ':
+		name = "Space"
+	case event.Key() == tcell.KeyRune:
+		name = string(event.Rune())
+	case event.Key() >= tcell.KeyCtrlA && event.Key() <= tcell.KeyCtrlZ && event.Key() != tcell.KeyTab && event.Key() != tcell.KeyEnter && event.Key() != tcell.KeyBackspace:
+		// Control characters are reported without ModCtrl by some terminals
+		mods = []string{"Ctrl"}
+		name = string(rune('A' + event.Key() - tcell.KeyCtrlA))
+	default:
+		name = tcell.KeyNames[event.Key()]
+		if event.Modifiers()&tcell.ModShift != 0 {
+			mods = append([]string{"Shift"}, mods...)
+		}
+	}
+
+	return strings.Join(append(mods, name), "+")
+}
+
+// namedKeys maps lower-cased key names accepted in the config to the names used by keyName
+var namedKeys = func() map[string]string {
+	names := map[string]string{"space": "Space"}
+	for _, name := range tcell.KeyNames {
+		if !strings.HasPrefix(name, "Ctrl-") {
+			names[strings.ToLower(name)] = name
+		}
+	}
+	return names
+}()
+
+// parseKeySpec turns a configured key sequence into the form used by the keymap.
+// Keystrokes are separated by spaces ("Ctrl+X Ctrl+S"); a word that is not a key
+// name is a sequence of single keys ("gg"). Emacs-style "C-x" and "M-x" are accepted.
+func parseKeySpec(spec string) (string, error) {
+	fields := strings.Fields(spec)
+	if len(fields) == 0 {
+		return "", fmt.Errorf("empty key")
+	}
+
+	var keys []string
+	for _, field := range fields {
+		key, err := parseKeystroke(field)
+		if err != nil {
+			// Not a single key: try it as a run of single-character keys
+			runes := []rune(field)
+			if len(runes) < 2 || strings.Contains(field, "+") {
+				return "", err
+			}
+			for _, r := range runes {
+				keys = append(keys, keyRuneName(r))
+			}
+			continue
+		}
+		keys = append(keys, key)
+	}
+	return strings.Join(keys, " "), nil
+}
+
+// parseKeystroke parses one key with optional modifiers, e.g. "j", "Ctrl+C", "alt+x", "PgDn"
+func parseKeystroke(field string) (string, error) {
+	switch {
+	case strings.HasPrefix(field, "C-") && len(field) > 2:
+		field = "Ctrl+" + field[2:]
+	case strings.HasPrefix(field, "M-") && len(field) > 2:
+		field = "Alt+" + field[2:]
+	}
+
+	parts := strings.Split(field, "+")
+	base := parts[len(parts)-1]
+	if base == "" && len(parts) > 1 {
+		// "Ctrl++" binds the plus key
+		base, parts = "+", parts[:len(parts)-2]
+	} else {
+		parts = parts[:len(parts)-1]
+	}
+
+	var shift, ctrl, alt bool
+	for _, mod := range parts {
+		switch strings.ToLower(mod) {
+		case "shift", "s":
+			shift = true
+		case "ctrl", "control", "c":
+			ctrl = true
+		case "alt", "meta", "m":
+			alt = true
+		default:
+			return "", fmt.Errorf("unknown modifier %q in %q", mod, field)
+		}
+	}
+
+	runes := []rune(base)
+	var name string
+	switch {
+	case len(runes) == 1 && ctrl:
+		if !unicode.IsLetter(runes[0]) || runes[0] > unicode.MaxASCII {
+			return "", fmt.Errorf("%q: Ctrl can only be combined with letters and named keys", field)
+		}
+		name = string(unicode.ToUpper(runes[0]))
+	case len(runes) == 1 && shift:
+		name, shift = string(unicode.ToUpper(runes[0])), false
+	case len(runes) == 1:
+		name = keyRuneName(runes[0])
+	default:
+		named, ok := namedKeys[strings.ToLower(base)]
+		if !ok {
+			return "", fmt.Errorf("unknown key %q", field)
+		}
+		name = named
+	}
+
+	var mods []string
+	if shift {
+		mods = append(mods, "Shift")
+	}
+	if ctrl {
+		mods = append(mods, "Ctrl")
+	}
+	if alt {
+		mods = append(mods, "Alt")
+	}
+	return strings.Join(append(mods, name), "+"), nil
+}
+
+func keyRuneName(r rune) string {
+	if r == ' ' {
+		return "Space"
+	}
+	return string(r)
+}
+
+// buildKeymap applies the configured keys on top of the default keymap and validates the result
+func buildKeymap(overrides map[string]keyList) ([]action, error) {
+	keymap := defaultKeymap()
+
+	index := make(map[string]int)
+	for i, a := range keymap {
+		index[a.Name] = i
+	}
+
+	// Sorted for deterministic error messages
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		i, ok := index[name]
+		if !ok {
+			return nil, fmt.Errorf("keys: unknown action %q", name)
+		}
+		keys := []string{}
+		for _, spec := range overrides[name] {
+			key, err := parseKeySpec(spec)
+			if err != nil {
+				return nil, fmt.Errorf("keys: %s: %w", name, err)
+			}
+			keys = append(keys, key)
+		}
+		keymap[i].Keys = keys
+	}
+
+	if err := validateKeymap(keymap); err != nil {
+		return nil, err
+	}
+	return keymap, nil
+}
+
+// validateKeymap reports keys bound to two actions in the same view, including a
+// sequence that can never complete because one of its prefixes is bound too
+func validateKeymap(keymap []action) error {
+	for _, view := range viewTitles {
+		bound := make(map[string]string)
+		for _, a := range keymap {
+			if !a.appliesTo(view.name) {
+				continue
+			}
+			for _, key := range a.Keys {
+				if other, ok := bound[key]; ok && other != a.Name {
+					return fmt.Errorf("keys: %q is bound to both %q and %q in the %s view", key, other, a.Name, view.title)
+				}
+				bound[key] = a.Name
+			}
+		}
+		for key, name := range bound {
+			fields := strings.Fields(key)
+			for n := 1; n < len(fields); n++ {
+				prefix := strings.Join(fields[:n], " ")
+				if other, ok := bound[prefix]; ok {
+					return fmt.Errorf("keys: %q (%s) hides %q (%s) in the %s view", prefix, other, key, name, view.title)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// appliesTo reports whether the action is available on the given page
+func (a action) appliesTo(view string) bool {
+	if len(a.Views) == 0 {
+		return true
+	}
+	for _, v := range a.Views {
+		if v == view {
+			return true
+		}
+	}
+	return false
+}
+
+// handleInput dispatches a key to the first matching action in the keymap.
+// Keys that start a multi-key sequence are held until the sequence completes.
+func (s *session) handleInput(event *tcell.EventKey) *tcell.EventKey {
+	name := keyName(event)
+
+	// Let text inputs (e.g. the list filter) receive every key
+	if _, typing := s.app.GetFocus().(*tview.InputField); typing && name != "Ctrl+C" {
+		return event
+	}
+
+	view := s.frontPage()
+	pending := s.pending
+	s.pending = nil
+
+	sequence := strings.Join(append(pending, name), " ")
+	prefix := false
+	for _, a := range s.keymap {
+		if !a.appliesTo(view) {
+			continue
+		}
+		for _, key := range a.Keys {
+			if key == sequence {
+				if len(pending) > 0 {
+					s.setStatus("")
+				}
+				return a.Run(s, event)
+			}
+			if strings.HasPrefix(key, sequence+" ") {
+				prefix = true
+			}
+		}
+	}
+
+	if prefix {
+		s.pending = append(pending, name)
+		s.setStatus(strings.Join(s.pending, " ") + " …")
+		return nil
+	}
+	if len(pending) > 0 {
+		// Not a known sequence: drop what was pending and try the key on its own
+		s.setStatus("")
+		return s.handleInput(event)
+	}
+
+	return event
+}
+
+// bindingsFor returns the actions available on a page
+func (s *session) bindingsFor(view string) []action {
+	var actions []action
+	for _, a := range s.keymap {
+		if a.appliesTo(view) {
+			actions = append(actions, a)
+		}
+	}
+	return actions
+}
+
+// toggleHelp shows the bindings available on the current page, or closes help
+func (s *session) toggleHelp() {
+	view := s.frontPage()
+	if view == "help" {
+		s.backPage()
+		return
+	}
+
+	var lines []string
+	for _, a := range s.bindingsFor(view) {
+		lines = append(lines, paint(theme.Title, fmt.Sprintf("%-14s", tview.Escape(strings.Join(a.Keys, ", "))))+" "+a.Description)
+	}
+
+	text := tview.NewTextView().
+		SetText(strings.Join(lines, "\n")).
+		SetDynamicColors(true).
+		SetScrollable(true)
+	text.SetTitle(" Keys: " + viewTitle(view) + " ").SetBorder(true)
+
+	// Center the help box over the current page
+	width, height := 48, len(lines)+2
+	overlay := tview.NewFlex().
+		AddItem(nil, 0, 1, false).
+		AddItem(tview.NewFlex().SetDirection(tview.FlexRow).
+			AddItem(nil, 0, 1, false).
+			AddItem(text, height, 0, true).
+			AddItem(nil, 0, 1, false), width, 0, true).
+		AddItem(nil, 0, 1, false)
+
+	s.pushView(navEntry{name: "help", item: overlay, overlay: true})
+}
+
+func viewTitle(view string) string {
+	for _, v := range viewTitles {
+		if v.name == view {
+			return v.title
+		}
+	}
+	return view
+}
+
+// writeKeymapMarkdown writes a keymap as the Markdown table used in the README
+func writeKeymapMarkdown(w io.Writer, keymap []action) {
+	fmt.Fprintln(w, "| Key | Action | Views |")
+	fmt.Fprintln(w, "|-----|--------|-------|")
+	for _, a := range keymap {
+		var keys []string
+		for _, key := range a.Keys {
+			keys = append(keys, "`"+key+"`")
+		}
+
+		views := "All"
+		if len(a.Views) > 0 {
+			var titles []string
+			for _, v := range a.Views {
+				titles = append(titles, viewTitle(v))
+			}
+			views = strings.Join(titles, ", ")
+		}
+
+		fmt.Fprintf(w, "| %s | %s | %s |\n", strings.Join(keys, " / "), a.Description, views)
+	}
+}
+
+// runKeys implements `gn-text keys`, which prints the effective key bindings
+func runKeys(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("keys", flag.ContinueOnError)
+	fs.SetOutput(w)
+	markdown := fs.Bool("markdown", false, "Print the Markdown table used in the README")
+	defaults := fs.Bool("defaults", false, "Ignore the config file and print the default bindings")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	keymap := defaultKeymap()
+	if !*defaults {
+		var err error
+		keymap, err = buildKeymap(settings.Keys)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "gn-text:", err)
+			return 1
+		}
+	}
+
+	if *markdown {
+		writeKeymapMarkdown(w, keymap)
+		return 0
+	}
+
+	defaultKeys := make(map[string]string)
+	for _, a := range defaultKeymap() {
+		defaultKeys[a.Name] = strings.Join(a.Keys, ", ")
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "ACTION\tKEYS\tVIEWS\tDESCRIPTION")
+	for _, a := range keymap {
+		views := "all"
+		if len(a.Views) > 0 {
+			views = strings.Join(a.Views, ",")
+		}
+		keys := strings.Join(a.Keys, ", ")
+		if keys != defaultKeys[a.Name] {
+			keys += " (config)"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", a.Name, keys, views, a.Description)
+	}
+	tw.Flush()
+	return 0
+}
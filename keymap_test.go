@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestKeyName(t *testing.T) {
+	tests := []struct {
+		event    *tcell.EventKey
+		expected string
+	}{
+		{tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone), "j"},
+		{tcell.NewEventKey(tcell.KeyRune, 'N', tcell.ModNone), "N"},
+		{tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone), "Space"},
+		{tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModAlt), "Alt+x"},
+		{tcell.NewEventKey(tcell.KeyCtrlC, 0, tcell.ModCtrl), "Ctrl+C"},
+		{tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone), "Right"},
+		{tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone), "Esc"},
+		{tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone), "Enter"},
+	}
+
+	for _, test := range tests {
+		if result := keyName(test.event); result != test.expected {
+			t.Errorf("keyName(%s) = %q, expected %q", test.event.Name(), result, test.expected)
+		}
+	}
+}
+
+func TestDefaultKeymapHasNoConflicts(t *testing.T) {
+	if err := validateKeymap(defaultKeymap()); err != nil {
+		t.Errorf("Unexpected conflict in default keymap: %v", err)
+	}
+}
+
+func TestParseKeySpec(t *testing.T) {
+	tests := []struct {
+		spec     string
+		expected string
+	}{
+		{"j", "j"},
+		{"G", "G"},
+		{"gg", "g g"},
+		{"g g", "g g"},
+		{"space", "Space"},
+		{"enter", "Enter"},
+		{"pgdn", "PgDn"},
+		{"ctrl+c", "Ctrl+C"},
+		{"C-n", "Ctrl+N"},
+		{"M-x", "Alt+x"},
+		{"Alt+Shift+x", "Alt+X"},
+		{"Shift+Right", "Shift+Right"},
+		{"Ctrl+X Ctrl+S", "Ctrl+X Ctrl+S"},
+		{"?", "?"},
+	}
+
+	for _, test := range tests {
+		result, err := parseKeySpec(test.spec)
+		if err != nil {
+			t.Errorf("parseKeySpec(%q) returned error: %v", test.spec, err)
+			continue
+		}
+		if result != test.expected {
+			t.Errorf("parseKeySpec(%q) = %q, expected %q", test.spec, result, test.expected)
+		}
+	}
+
+	for _, invalid := range []string{"", "Hyper+x", "Ctrl+Nope", "Ctrl+1"} {
+		if _, err := parseKeySpec(invalid); err == nil {
+			t.Errorf("Expected error for %q", invalid)
+		}
+	}
+}
+
+func TestBuildKeymap(t *testing.T) {
+	keymap, err := buildKeymap(map[string]keyList{
+		"down":   {"C-n", "j"},
+		"author": {},
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	for _, a := range keymap {
+		switch a.Name {
+		case "down":
+			if strings.Join(a.Keys, ",") != "Ctrl+N,j" {
+				t.Errorf("Expected remapped down keys, got %v", a.Keys)
+			}
+		case "author":
+			if len(a.Keys) != 0 {
+				t.Errorf("Expected author to be unbound, got %v", a.Keys)
+			}
+		case "up":
+			if strings.Join(a.Keys, ",") != "k,Up" {
+				t.Errorf("Expected default up keys, got %v", a.Keys)
+			}
+		}
+	}
+}
+
+func TestBuildKeymapErrors(t *testing.T) {
+	tests := []struct {
+		keys     map[string]keyList
+		expected string
+	}{
+		{map[string]keyList{"fly": {"f"}}, `unknown action "fly"`},
+		{map[string]keyList{"refresh": {"j"}}, `"j" is bound to both`},
+		{map[string]keyList{"refresh": {"g"}}, `hides "g g"`},
+		{map[string]keyList{"quit": {"Hyper+q"}}, `unknown modifier`},
+	}
+
+	for _, test := range tests {
+		_, err := buildKeymap(test.keys)
+		if err == nil || !strings.Contains(err.Error(), test.expected) {
+			t.Errorf("buildKeymap(%v) error = %v, expected %q", test.keys, err, test.expected)
+		}
+	}
+}
+
+func TestHandleInputKeySequence(t *testing.T) {
+	s := newSession(tview.NewApplication(), nil, defaultKeymap())
+
+	if result := s.handleInput(tcell.NewEventKey(tcell.KeyRune, 'g', tcell.ModNone)); result != nil {
+		t.Fatalf("Expected first key of a sequence to be held, got %v", result.Name())
+	}
+	result := s.handleInput(tcell.NewEventKey(tcell.KeyRune, 'g', tcell.ModNone))
+	if result == nil || result.Key() != tcell.KeyHome {
+		t.Fatalf("Expected 'g g' to go to top")
+	}
+
+	// An unfinished sequence falls back to the key on its own
+	s.handleInput(tcell.NewEventKey(tcell.KeyRune, 'g', tcell.ModNone))
+	result = s.handleInput(tcell.NewEventKey(tcell.KeyRune, 'j', tcell.ModNone))
+	if result == nil || result.Key() != tcell.KeyDown {
+		t.Errorf("Expected 'j' after 'g' to move down")
+	}
+}
+
+func TestReadmeKeyTableIsGenerated(t *testing.T) {
+	readme, err := os.ReadFile("README.md")
+	if err != nil {
+		t.Fatalf("Failed to read README: %v", err)
+	}
+
+	var table bytes.Buffer
+	writeKeymapMarkdown(&table, defaultKeymap())
+
+	if !strings.Contains(string(readme), table.String()) {
+		t.Errorf("README key table is out of date; regenerate it with `gn-text keys -defaults -markdown`:\n%s", table.String())
+	}
+}
@@ -0,0 +1,225 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+)
+
+// listSections maps the sections of GeekNews to their pages. The front page ("top") is read
+// from the feed and enriched from the homepage; the others are read from their pages.
+var listSections = map[string]string{
+	"top":  "",
+	"new":  "new",
+	"ask":  "ask",
+	"show": "show",
+}
+
+// listFields are the article fields `gn-text list` can print, in default order
+var listFields = []string{"id", "title", "url", "link", "domain", "author", "points", "comments"}
+
+// defaultListFields are printed by the text and TSV formats unless --fields is given
+var defaultListFields = []string{"id", "title", "domain", "url"}
+
+// runList prints the article list of a section without starting the UI
+func runList(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("list", flag.ContinueOnError)
+	fs.SetOutput(w)
+	format := fs.String("format", "text", "Output format: text, json or tsv")
+	limit := fs.Int("limit", 0, "Print at most this many articles (0 prints all)")
+	section := fs.String("section", "top", "Section: top, new, ask or show")
+	fieldList := fs.String("fields", "", "Comma-separated fields to print ("+strings.Join(listFields, ",")+")")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	if *format != "text" && *format != "json" && *format != "tsv" {
+		fmt.Fprintf(os.Stderr, "gn-text: unknown format %q (available: text, json, tsv)\n", *format)
+		return 2
+	}
+	fields, err := parseListFields(*fieldList, *format)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 2
+	}
+
+	articles, err := fetchSection(*section, fetchCached)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	if *limit > 0 && len(articles) > *limit {
+		articles = articles[:*limit]
+	}
+
+	switch *format {
+	case "json":
+		err = writeJSON(w, newArticleList(*section, articles, time.Now()))
+	case "tsv":
+		writeListTSV(w, articles, fields)
+	default:
+		writeListText(w, articles, fields)
+	}
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	return 0
+}
+
+// parseListFields returns the fields named in list, or the default fields. JSON always
+// prints every field, as the versioned schema defines it.
+func parseListFields(list, format string) ([]string, error) {
+	if strings.TrimSpace(list) == "" {
+		return defaultListFields, nil
+	}
+	if format == "json" {
+		return nil, errors.New("--fields applies to the text and tsv formats; json prints every field")
+	}
+
+	var fields []string
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if !containsString(listFields, field) {
+			return nil, fmt.Errorf("unknown field %q (available: %s)", field, strings.Join(listFields, ", "))
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+// fetchSection fetches the articles of a section with fetch
+func fetchSection(section string, fetch func(string) (string, error)) ([]Article, error) {
+	path, ok := listSections[section]
+	if !ok {
+		return nil, fmt.Errorf("unknown section %q (available: top, new, ask, show)", section)
+	}
+
+	if section != "top" {
+		html, err := fetch(geekNewsBaseURL + path)
+		if err != nil {
+			return nil, err
+		}
+		articles, _, err := parseGeekNewsTopicList(html)
+		return articles, err
+	}
+
+	feed, err := fetch(geekNewsRSSURL)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := parseGeekNewsRSS(feed)
+	if err != nil {
+		return nil, err
+	}
+	// The homepage adds links, points and comment counts; the feed alone is still a list
+	if html, err := fetch(geekNewsBaseURL); err == nil {
+		if topics, _, err := parseGeekNewsTopicList(html); err == nil {
+			enrichArticles(articles, topics)
+		}
+	}
+	return articles, nil
+}
+
+// enrichArticles fills in the fields the feed lacks from the same topics on the homepage
+func enrichArticles(articles, topics []Article) {
+	byID := make(map[string]Article)
+	for _, topic := range topics {
+		if id := extractTopicID(topic.CommentsLink); id != "" {
+			byID[id] = topic
+		}
+	}
+
+	for i := range articles {
+		topic, ok := byID[extractTopicID(articles[i].CommentsLink)]
+		if !ok {
+			continue
+		}
+		a := &articles[i]
+		if a.Link == "" && strings.HasPrefix(topic.Link, "http") {
+			a.Link = topic.Link
+			a.Domain = topic.Domain
+		}
+		if a.Author == "" {
+			a.Author = topic.Author
+		}
+		if a.AuthorID == "" {
+			a.AuthorID = topic.AuthorID
+		}
+		if a.Points == "" {
+			a.Points = topic.Points
+		}
+		if a.Comments == "" {
+			a.Comments = topic.Comments
+		}
+	}
+}
+
+// listField returns a field of article as printed by `gn-text list`
+func listField(article Article, field string) string {
+	switch field {
+	case "id":
+		return extractTopicID(article.CommentsLink)
+	case "title":
+		return article.Title
+	case "url":
+		return article.CommentsLink
+	case "link":
+		if strings.HasPrefix(article.Link, "http") {
+			return article.Link
+		}
+		return "" // Posts without an external link (Ask GN) link to themselves
+	case "domain":
+		return article.Domain
+	case "author":
+		return article.Author
+	case "points":
+		return article.Points
+	case "comments":
+		return article.Comments
+	}
+	return ""
+}
+
+// writeListText prints one article per line, its fields separated by two spaces
+func writeListText(w io.Writer, articles []Article, fields []string) {
+	for _, article := range articles {
+		fmt.Fprintln(w, strings.Join(listValues(article, fields), "  "))
+	}
+}
+
+// listValues returns the non-empty fields of article
+func listValues(article Article, fields []string) []string {
+	var values []string
+	for _, field := range fields {
+		if value := listField(article, field); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// writeListTSV prints one article per line, its fields separated by tabs
+func writeListTSV(w io.Writer, articles []Article, fields []string) {
+	clean := strings.NewReplacer("\t", " ", "\n", " ", "\r", " ")
+	for _, article := range articles {
+		values := make([]string, len(fields))
+		for i, field := range fields {
+			values[i] = clean.Replace(listField(article, field))
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+}
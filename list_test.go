@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// testdataFetch serves the feed and homepage from testdata
+func testdataFetch(t *testing.T, homepage bool) func(string) (string, error) {
+	return func(url string) (string, error) {
+		var path string
+		switch url {
+		case geekNewsRSSURL:
+			path = "testdata/geeknews_feed.xml"
+		case geekNewsBaseURL, geekNewsBaseURL + "new":
+			if !homepage {
+				return "", errors.New("offline")
+			}
+			path = "testdata/geeknews_homepage_topics.html"
+		default:
+			t.Fatalf("Unexpected fetch of %s", url)
+		}
+		data, err := os.ReadFile(path)
+		return string(data), err
+	}
+}
+
+func TestFetchSectionEnrichesFeed(t *testing.T) {
+	articles, err := fetchSection("top", testdataFetch(t, true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var enriched *Article
+	for i := range articles {
+		if extractTopicID(articles[i].CommentsLink) == "26364" {
+			enriched = &articles[i]
+		}
+	}
+	if enriched == nil {
+		t.Fatal("Expected topic 26364 in the feed")
+	}
+	if enriched.Points == "" || enriched.Comments == "" {
+		t.Errorf("Expected points and comments from the homepage, got %+v", *enriched)
+	}
+
+	plain, err := fetchSection("top", testdataFetch(t, false))
+	if err != nil || len(plain) != len(articles) {
+		t.Errorf("Expected the feed alone without the homepage, got %d articles, %v", len(plain), err)
+	}
+
+	if _, err := fetchSection("best", testdataFetch(t, true)); err == nil {
+		t.Error("Expected an error for an unknown section")
+	}
+}
+
+func TestListFormats(t *testing.T) {
+	articles := []Article{
+		{Title: "Go\t1.26 & more", CommentsLink: geekNewsBaseURL + "topic?id=1", Link: "https://go.dev/", Domain: "go.dev", Points: "10", Comments: "3"},
+		{Title: "Ask GN", CommentsLink: geekNewsBaseURL + "topic?id=2", Link: "topic?id=2", Domain: "news.hada.io"},
+	}
+
+	var out bytes.Buffer
+	writeListText(&out, articles, defaultListFields)
+	if got := strings.Split(out.String(), "\n")[0]; got != "1  Go\t1.26 & more  go.dev  https://news.hada.io/topic?id=1" {
+		t.Errorf("Unexpected text line %q", got)
+	}
+
+	out.Reset()
+	writeListTSV(&out, articles, []string{"id", "title", "link", "points"})
+	expected := "1\tGo 1.26 & more\thttps://go.dev/\t10\n2\tAsk GN\t\t\n"
+	if out.String() != expected {
+		t.Errorf("Expected TSV %q, got %q", expected, out.String())
+	}
+
+	out.Reset()
+	if err := writeJSON(&out, newArticleList("top", articles, time.Now())); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	var decoded ArticleListDocument
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", out.String(), err)
+	}
+	a := decoded.Articles
+	if len(a) != 2 || a[0].Title != "Go\t1.26 & more" || a[1].Link != nil || *a[0].CommentCount != 3 {
+		t.Errorf("Unexpected JSON %q", out.String())
+	}
+	if strings.Contains(out.String(), `\u0026`) {
+		t.Errorf("Expected no HTML escapes, got %q", out.String())
+	}
+
+	out.Reset()
+	writeJSON(&out, newArticleList("top", nil, time.Now()))
+	if !strings.Contains(out.String(), `"articles": []`) {
+		t.Errorf("Expected an empty array, got %q", out.String())
+	}
+}
+
+func TestParseListFields(t *testing.T) {
+	if _, err := parseListFields("title", "json"); err == nil {
+		t.Error("Expected an error for fields with the json format")
+	}
+	if fields, _ := parseListFields("title, url", "tsv"); strings.Join(fields, ",") != "title,url" {
+		t.Errorf("Expected the given fields, got %v", fields)
+	}
+	if _, err := parseListFields("title,score", "text"); err == nil || !strings.Contains(err.Error(), "score") {
+		t.Errorf("Expected an unknown field error, got %v", err)
+	}
+}
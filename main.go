@@ -3,8 +3,10 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"time"
 
 	"github.com/rivo/tview"
 )
@@ -16,6 +18,8 @@ var geekNewsRSSURL = "https://news.hada.io/rss/news"
 func main() {
 	versionFlag := flag.Bool("v", false, "Print version and exit")
 	flag.BoolVar(versionFlag, "version", false, "Print version and exit")
+	configFlag := flag.String("config", "", "Config file (default $GN_TEXT_CONFIG or ~/.config/gn-text/config.yaml)")
+	overrides := registerConfigFlags(flag.CommandLine)
 	flag.Parse()
 
 	if *versionFlag {
@@ -23,25 +27,178 @@ func main() {
 		os.Exit(0)
 	}
 
-	app := tview.NewApplication()
+	if err := setupSettings(*configFlag, overrides, flag.Arg(0), os.Stderr); err != nil {
+		log.Fatal(err)
+	}
+	setupCache()
+
+	if flag.NArg() > 0 {
+		os.Exit(runCommand(flag.Arg(0), flag.Args()[1:]))
+	}
 
-	rssContent, err := fetchWebpage(geekNewsRSSURL)
+	keymap, err := buildKeymap(settings.Keys)
 	if err != nil {
+		log.Fatalf("%s: %v", settings.Path, err)
+	}
+
+	app := tview.NewApplication().EnableMouse(true)
+
+	if err := setupState(); err != nil {
 		log.Fatal(err)
 	}
 
-	articles, err := parseGeekNewsRSS(rssContent)
+	articles, err := fetchArticles()
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	list := createArticleList(articles)
-	pages := tview.NewPages()
-	pages.AddPage("homepage", list, true, false)
-
-	app.SetInputCapture(createInputHandler(app, list, articles, pages))
+	s := newSession(app, articles, keymap)
+	app.SetInputCapture(s.handleInput)
 
-	if err := app.SetRoot(list, true).Run(); err != nil {
+	if err := app.SetRoot(s.root, true).Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// runCommand runs a non-interactive subcommand and returns the process exit code
+func runCommand(name string, args []string) int {
+	switch name {
+	case "doctor":
+		return runDoctor(args, os.Stdout)
+	case "keys":
+		return runKeys(args, os.Stdout)
+	case "config":
+		return runConfig(args, os.Stdout)
+	case "list":
+		return runList(args, os.Stdout)
+	case "show":
+		return runShow(args, os.Stdout)
+	case "export":
+		return runExport(args, os.Stdout)
+	case "watch":
+		return runWatch(args, os.Stdout)
+	case "feed":
+		return runFeed(args, os.Stdout)
+	case "digest":
+		return runDigest(args, os.Stdout)
+	case "serve":
+		return runServe(args, os.Stdout)
+	default:
+		fmt.Fprintf(os.Stderr, "gn-text: unknown command %q\n", name)
+		return 2
+	}
+}
+
+// parseInterspersed parses args with fs, accepting flags after the arguments as well
+// (gn-text show 12345 --no-comments), and returns the arguments. It reports false if
+// the flags do not parse.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, bool) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, false
+		}
+		if fs.NArg() == 0 {
+			return rest, true
+		}
+		rest = append(rest, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// setupSettings sets up settings and theme from the config file. The doctor and config
+// commands, which help fix a broken config, fall back to the defaults and report the
+// error on stderr instead of failing.
+func setupSettings(path string, overrides *configFlags, command string, stderr io.Writer) error {
+	cfg, err := setupConfig(path, overrides)
+	var active Theme
+	if err == nil {
+		active, err = cfg.activeTheme()
+	}
+	if err != nil {
+		if command != "doctor" && command != "config" {
+			return err
+		}
+		fmt.Fprintln(stderr, "gn-text:", err)
+		fmt.Fprintln(stderr, "gn-text: using the default settings")
+		cfg = defaultConfig()
+		cfg.Path, _ = resolveConfigPath(path)
+		if _, err := os.Stat(cfg.Path); err == nil {
+			cfg.Found = true
+		}
+		active, _ = cfg.activeTheme() // The default theme is built in
+	}
+	settings, theme = cfg, active
+	theme.apply()
+	return nil
+}
+
+// resolveConfigPath returns path, or if it is empty $GN_TEXT_CONFIG or the default location
+func resolveConfigPath(path string) (string, error) {
+	if path == "" {
+		path = os.Getenv("GN_TEXT_CONFIG")
+	}
+	if path == "" {
+		return configPath()
+	}
+	return path, nil
+}
+
+// setupConfig loads the config file and applies environment and command line overrides
+func setupConfig(path string, overrides *configFlags) (*Config, error) {
+	path, err := resolveConfigPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := loadConfig(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	overrides.apply(cfg)
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// setupCache sets up the page cache and removes expired pages from disk
+func setupCache() {
+	dir, err := pageCacheDir()
+	if err != nil {
+		dir = "" // Memory cache only
+	}
+	cache = newPageCache(dir,
+		time.Duration(settings.CacheTTLMemory)*time.Second,
+		time.Duration(settings.CacheTTLDisk)*time.Second)
+	cache.cleanup()
+}
+
+// setupState loads the log of opened topics and read articles, the bookmarks and the history
+func setupState() error {
+	path, err := readLogPath()
+	if err != nil {
+		return err
+	}
+	if reads, err = loadReadLog(path); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if path, err = bookmarksPath(); err != nil {
+		return err
+	}
+	if bookmarks, err = loadBookmarks(path); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if path, err = historyPath(); err != nil {
+		return err
+	}
+	if history, err = loadHistory(path); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+	return nil
+}
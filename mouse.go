@@ -0,0 +1,183 @@
+package main
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
+)
+
+// footnoteRegex matches a footnote reference such as "[3]"
+var footnoteRegex = regexp.MustCompile(`\[(\d+)\]`)
+
+// listMouse makes a click select a list row and a double click open it, like 'l'.
+// tview would otherwise run the list's selected function on a single click.
+func (s *session) listMouse(list *tview.List) {
+	list.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		if action != tview.MouseLeftClick && action != tview.MouseLeftDoubleClick {
+			return action, event
+		}
+		index := listIndexAt(list, event)
+		if index < 0 {
+			return action, nil
+		}
+		s.app.SetFocus(list)
+		list.SetCurrentItem(index)
+		if action == tview.MouseLeftDoubleClick {
+			s.nextPage()
+		}
+		return action, nil
+	})
+}
+
+// listIndexAt returns the index of the list item under the mouse, or -1
+func listIndexAt(list *tview.List, event *tcell.EventMouse) int {
+	x, y := event.Position()
+	if !list.InInnerRect(x, y) {
+		return -1
+	}
+	_, top, _, _ := list.GetInnerRect()
+	offset, _ := list.GetOffset()
+	index := offset + (y-top)/2 // Every list shows secondary text, so items take two rows
+	if index >= list.GetItemCount() {
+		return -1
+	}
+	return index
+}
+
+// textMouse handles clicks on the text of a page: links and footnote references are passed
+// to onLink, other lines to onClick. keepFocus stops clicks from focusing the view.
+func (p *textPage) textMouse(keepFocus bool, onLink func(url string), onClick func(row int) bool) {
+	p.view.SetMouseCapture(func(action tview.MouseAction, event *tcell.EventMouse) (tview.MouseAction, *tcell.EventMouse) {
+		switch action {
+		case tview.MouseLeftDown:
+			if keepFocus {
+				return action, nil
+			}
+		case tview.MouseScrollUp, tview.MouseScrollDown:
+			// Scroll here: tview's own wheel handling jumps to the end of text it has not laid out yet
+			if !p.view.InRect(event.Position()) {
+				break
+			}
+			p.scroll(action == tview.MouseScrollDown)
+			return action, nil
+		case tview.MouseLeftClick:
+			x, y := event.Position()
+			if !p.view.InInnerRect(x, y) {
+				break
+			}
+			left, top, _, _ := p.view.GetInnerRect()
+			offset, _ := p.view.GetScrollOffset()
+			row := offset + y - top
+
+			lines := strings.Split(p.view.GetText(true), "\n")
+			if row >= len(lines) {
+				break
+			}
+			if url := linkAt(lines, row, x-left); url != "" {
+				onLink(url)
+				return action, nil
+			}
+			if onClick != nil && onClick(row) {
+				return action, nil
+			}
+		}
+		return action, event
+	})
+}
+
+// scroll moves the text one line down or up, stopping at the last page
+func (p *textPage) scroll(down bool) {
+	row, _ := p.view.GetScrollOffset()
+	if !down {
+		p.view.ScrollTo(max(row-1, 0), 0)
+		return
+	}
+	_, _, _, height := p.view.GetInnerRect()
+	lines := strings.Count(p.text, "\n") + 1
+	p.view.ScrollTo(min(row+1, max(lines-height, 0)), 0)
+}
+
+// linkAt returns the URL at column col of lines[row]: a link, or the link a footnote
+// reference points to
+func linkAt(lines []string, row, col int) string {
+	line := lines[row]
+	for _, loc := range linkRegex.FindAllStringIndex(line, -1) {
+		if spans(line, loc, col) {
+			return line[loc[0]:loc[1]]
+		}
+	}
+	for _, loc := range footnoteRegex.FindAllStringSubmatchIndex(line, -1) {
+		if spans(line, loc, col) {
+			return footnoteURL(lines, line[loc[0]:loc[1]])
+		}
+	}
+	return ""
+}
+
+// spans reports whether the match loc of line covers display column col
+func spans(line string, loc []int, col int) bool {
+	start := runewidth.StringWidth(line[:loc[0]])
+	end := start + runewidth.StringWidth(line[loc[0]:loc[1]])
+	return col >= start && col < end
+}
+
+// footnoteURL returns the URL of the footnote line starting with ref ("[3] https://..."), if any
+func footnoteURL(lines []string, ref string) string {
+	for _, line := range lines {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), ref)
+		if !ok {
+			continue
+		}
+		if url := linkRegex.FindString(rest); url != "" && strings.HasPrefix(strings.TrimSpace(rest), url) {
+			return url
+		}
+	}
+	return ""
+}
+
+// foldAt returns the index of the first comment of the folded replies shown on row of the
+// comments page, or -1 if row is not a fold line
+func (s *session) foldAt(row int) int {
+	p := s.textPages["comments"]
+	if p == nil || s.page == nil {
+		return -1
+	}
+	lines := strings.Split(p.view.GetText(true), "\n")
+	if row >= len(lines) || !strings.HasPrefix(strings.TrimLeft(lines[row], " |"), foldMarker) {
+		return -1
+	}
+
+	// The nth fold line stands for the nth folded run
+	n := 0
+	for _, line := range lines[:row] {
+		if strings.HasPrefix(strings.TrimLeft(line, " |"), foldMarker) {
+			n++
+		}
+	}
+	runs := s.page.foldedRuns()
+	if n >= len(runs) {
+		return -1
+	}
+	return runs[n]
+}
+
+// expandFolds shows the folded replies starting at comment index start, or all of them if start is -1
+func (s *session) expandFolds(start int) {
+	p := s.textPages["comments"]
+	if p == nil || s.page == nil {
+		return
+	}
+	runs := s.page.foldedRuns()
+	if len(runs) == 0 {
+		return
+	}
+	for _, run := range runs {
+		if start < 0 || run == start {
+			s.page.expandFold(run)
+		}
+	}
+	p.relayout()
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+func TestLinkAt(t *testing.T) {
+	lines := []string{
+		"링크 https://example.com/a 참고",
+		"각주[1]와 [2]",
+		"",
+		"[1] https://example.com/footnote",
+	}
+
+	tests := []struct {
+		row, col int
+		expected string
+	}{
+		{0, 5, "https://example.com/a"},  // "링크 " is 5 columns wide
+		{0, 25, "https://example.com/a"}, // Last character of the link
+		{0, 26, ""},                      // Space after the link
+		{0, 2, ""},                       // Korean text before the link
+		{1, 5, "https://example.com/footnote"},
+		{1, 12, ""}, // [2] has no footnote line
+		{3, 6, "https://example.com/footnote"},
+	}
+	for _, tt := range tests {
+		if got := linkAt(lines, tt.row, tt.col); got != tt.expected {
+			t.Errorf("linkAt(row %d, col %d) = %q, expected %q", tt.row, tt.col, got, tt.expected)
+		}
+	}
+}
+
+func TestListIndexAt(t *testing.T) {
+	list := tview.NewList().ShowSecondaryText(true)
+	for _, title := range []string{"a", "b", "c"} {
+		list.AddItem(title, "domain", 0, nil)
+	}
+	list.SetRect(0, 1, 40, 10)
+
+	tests := []struct{ y, expected int }{{1, 0}, {2, 0}, {3, 1}, {6, 2}, {7, -1}, {0, -1}}
+	for _, tt := range tests {
+		event := tcell.NewEventMouse(5, tt.y, tcell.Button1, 0)
+		if got := listIndexAt(list, event); got != tt.expected {
+			t.Errorf("listIndexAt(y %d) = %d, expected %d", tt.y, got, tt.expected)
+		}
+	}
+}
+
+func TestFoldedReplies(t *testing.T) {
+	limit := settings.CommentDepthLimit
+	page := &TopicPage{Comments: []Comment{
+		{Author: "a", Body: "top"},
+		{Author: "deep1", Body: "deep", Depth: limit + 1},
+		{Author: "deep2", Body: "deeper", Depth: limit + 2},
+		{Author: "b", Body: "top"},
+		{Author: "deep3", Body: "deep", Depth: limit + 1},
+	}}
+
+	if runs := page.foldedRuns(); len(runs) != 2 || runs[0] != 1 || runs[1] != 4 {
+		t.Fatalf("Expected folded runs at 1 and 4, got %v", runs)
+	}
+
+	s := &session{textPages: make(map[string]*textPage), page: page}
+	p := newTextPage(func(width int) ([]string, []int) { return layoutTopicPage(page, width) })
+	s.textPages["comments"] = p
+	p.reflow(80)
+
+	if strings.Contains(p.text, "deep1") || !strings.Contains(p.text, "답글 2개 더 보기") {
+		t.Fatalf("Expected deep replies folded, got %q", p.text)
+	}
+
+	lines := strings.Split(p.view.GetText(true), "\n")
+	var foldRows []int
+	for row := range lines {
+		if s.foldAt(row) >= 0 {
+			foldRows = append(foldRows, row)
+		}
+	}
+	if len(foldRows) != 2 {
+		t.Fatalf("Expected 2 fold lines, got rows %v in %q", foldRows, lines)
+	}
+	if s.foldAt(foldRows[1]) != 4 {
+		t.Errorf("Expected second fold line to stand for comment 4, got %d", s.foldAt(foldRows[1]))
+	}
+
+	s.expandFolds(s.foldAt(foldRows[0]))
+	if !strings.Contains(p.text, "deep1") || !strings.Contains(p.text, "deep2") || strings.Contains(p.text, "deep3") {
+		t.Errorf("Expected only the first fold expanded, got %q", p.text)
+	}
+
+	s.expandFolds(-1)
+	if !strings.Contains(p.text, "deep3") || len(page.foldedRuns()) != 0 {
+		t.Errorf("Expected every fold expanded, got %q", p.text)
+	}
+}
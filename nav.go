@@ -0,0 +1,128 @@
+package main
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/rivo/tview"
+)
+
+// navEntry is a view on the navigation stack. It keeps the view's widgets, so that going
+// back or forward shows the view as it was left, scrolled where it was.
+type navEntry struct {
+	name string // Page name, which selects the key bindings
+	item tview.Primitive
+	text *textPage // Text page of the comments and article views
+
+	// Session state the view was shown with
+	topic Article
+	page  *TopicPage
+	user  *userView
+
+	overlay bool // Shown over the previous view and forgotten once left (help, author picker)
+}
+
+// showPage shows a view, pushing it on the navigation stack
+func (s *session) showPage(name string, item tview.Primitive) {
+	s.pushView(navEntry{name: name, item: item})
+}
+
+// pushView shows a view on top of the current one, dropping the views that were gone back
+// from. Showing the view already on top again replaces it.
+func (s *session) pushView(e navEntry) {
+	s.savePosition()
+	e.topic, e.page, e.user = s.topic, s.page, s.user
+
+	s.stack = s.stack[:s.pos+1]
+	if s.pos > 0 && s.stack[s.pos].item == e.item {
+		s.stack[s.pos] = e
+	} else {
+		if s.stack[s.pos].overlay {
+			s.stack = s.stack[:s.pos] // Leaving an overlay for another view closes it
+			s.pos--
+		}
+		s.stack = append(s.stack, e)
+		s.pos++
+	}
+
+	if e.text != nil {
+		s.textPages[e.name] = e.text
+	}
+	s.pages.AddPage(e.name, e.item, true, true)
+	s.setStatus("")
+}
+
+// backPage goes back to the previous view
+func (s *session) backPage() {
+	if s.pos == 0 {
+		return
+	}
+	s.savePosition()
+	if s.stack[s.pos].overlay {
+		s.stack = s.stack[:s.pos]
+	}
+	s.pos--
+	s.restoreView(s.stack[s.pos])
+}
+
+// forwardPage goes to the view that was gone back from
+func (s *session) forwardPage() {
+	if s.pos+1 >= len(s.stack) {
+		return
+	}
+	s.savePosition()
+	s.pos++
+	s.restoreView(s.stack[s.pos])
+}
+
+// homePage goes back to the article list, forgetting the other views
+func (s *session) homePage() {
+	s.savePosition()
+	s.stack = s.stack[:1]
+	s.pos = 0
+	s.restoreView(s.stack[0])
+}
+
+// restoreView shows a view from the navigation stack with the state it was shown with
+func (s *session) restoreView(e navEntry) {
+	s.topic, s.page, s.user = e.topic, e.page, e.user
+	if e.text != nil {
+		s.textPages[e.name] = e.text
+	}
+	s.pages.AddPage(e.name, e.item, true, false)
+	s.pages.SwitchToPage(e.name)
+	s.setStatus("")
+}
+
+// followLink opens a link clicked in a text view: GeekNews topics and user profiles are
+// shown in gn-text, on top of the current view, and anything else in the browser
+func (s *session) followLink(link string) {
+	switch kind, id := geekNewsLink(link); kind {
+	case "topic":
+		s.openComments(Article{CommentsLink: geekNewsBaseURL + "topic?id=" + id})
+	case "user":
+		s.openUser(id)
+	default:
+		openURL(link)
+	}
+}
+
+// geekNewsLink returns "topic" or "user" and the ID for a link to a GeekNews topic or user
+// profile, or "" for anything else. Topic IDs must be numbers, as they are put into URLs
+// and file names as they are.
+func geekNewsLink(link string) (kind, id string) {
+	u, err := url.Parse(link)
+	base, _ := url.Parse(geekNewsBaseURL)
+	if err != nil || strings.TrimPrefix(u.Host, "www.") != base.Host {
+		return "", ""
+	}
+
+	id = u.Query().Get("id")
+	switch {
+	case u.Path == "/topic" && isTopicID(id):
+		return "topic", id
+	case u.Path == "/user" && id != "":
+		return "user", id
+	}
+	return "", ""
+}
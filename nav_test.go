@@ -0,0 +1,104 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestNavigationStack(t *testing.T) {
+	s := newSession(tview.NewApplication(), nil, defaultKeymap())
+	long := strings.Repeat("줄\n", 100)
+
+	s.topic = topicArticle("1", "첫 토픽")
+	s.showText("comments", staticText(long))
+	first := s.textPages["comments"]
+	first.view.SetRect(0, 0, 40, 10)
+	first.reflow(40)
+	first.view.ScrollTo(30, 0)
+
+	s.topic = topicArticle("2", "링크로 연 토픽")
+	s.showText("comments", staticText("둘째"))
+	second := s.textPages["comments"]
+
+	s.backPage()
+	if s.frontPage() != "comments" || s.textPages["comments"] != first || s.topic.Title != "첫 토픽" {
+		t.Fatalf("Expected the first topic back, got %q %q", s.frontPage(), s.topic.Title)
+	}
+	if row, _ := first.view.GetScrollOffset(); row != 30 {
+		t.Errorf("Expected the scroll position kept, got row %d", row)
+	}
+
+	s.forwardPage()
+	if s.textPages["comments"] != second || s.topic.Title != "링크로 연 토픽" {
+		t.Errorf("Expected to go forward to the second topic, got %q", s.topic.Title)
+	}
+	s.forwardPage() // Nothing further
+	if s.pos != 2 {
+		t.Errorf("Expected to stay on the last view, at %d", s.pos)
+	}
+
+	// Help is an overlay: leaving it forgets it
+	s.toggleHelp()
+	if s.frontPage() != "help" {
+		t.Fatalf("Expected help, got %q", s.frontPage())
+	}
+	s.toggleHelp()
+	s.forwardPage()
+	if s.frontPage() != "comments" || len(s.stack) != 3 {
+		t.Errorf("Expected help to be forgotten, got %q with %d views", s.frontPage(), len(s.stack))
+	}
+
+	// Opening a view after going back drops the views gone back from
+	s.backPage()
+	s.openSaved()
+	s.openSaved() // Showing the same view again replaces it
+	if len(s.stack) != 3 || s.frontPage() != "saved" {
+		t.Errorf("Expected home, first topic and saved, got %d views ending at %q", len(s.stack), s.frontPage())
+	}
+
+	s.homePage()
+	if s.frontPage() != "homepage" || s.pos != 0 || len(s.stack) != 1 {
+		t.Errorf("Expected only the article list, got %q with %d views", s.frontPage(), len(s.stack))
+	}
+	s.backPage() // Nothing before the article list
+	if s.frontPage() != "homepage" {
+		t.Errorf("Expected to stay on the article list, got %q", s.frontPage())
+	}
+}
+
+func TestPageAuthorsUseAccountIDs(t *testing.T) {
+	s := newSession(tview.NewApplication(), nil, defaultKeymap())
+	s.topic = Article{Author: "GN⁺", AuthorID: "neo"}
+	s.page = &TopicPage{Comments: []Comment{
+		{Author: "kuthia", AuthorID: "kuthia"},
+		{Author: "GN⁺", AuthorID: "neo"},
+		{Author: "", AuthorID: ""},
+	}}
+
+	authors := s.pageAuthors()
+	if len(authors) != 2 || authors[0] != (pageAuthor{Name: "GN⁺", ID: "neo"}) || authors[1].ID != "kuthia" {
+		t.Errorf("Expected GN⁺ (neo) and kuthia, got %+v", authors)
+	}
+}
+
+func TestGeekNewsLink(t *testing.T) {
+	tests := []struct {
+		link, kind, id string
+	}{
+		{"https://news.hada.io/topic?id=26364", "topic", "26364"},
+		{"https://www.news.hada.io/topic?id=26364&go=comments", "topic", "26364"},
+		{"https://news.hada.io/user?id=neo", "user", "neo"},
+		{"https://news.hada.io/topic?id=../../x", "", ""},
+		{"https://news.hada.io/topic?id=1%26go%3Dcomments", "", ""},
+		{"https://news.hada.io/topic?id=1%23top", "", ""},
+		{"https://news.hada.io/topic", "", ""},
+		{"https://example.com/topic?id=1", "", ""},
+	}
+	for _, test := range tests {
+		if kind, id := geekNewsLink(test.link); kind != test.kind || id != test.id {
+			t.Errorf("geekNewsLink(%q) = %q, %q, expected %q, %q", test.link, kind, id, test.kind, test.id)
+		}
+	}
+}
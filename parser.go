@@ -6,26 +6,36 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// geekNewsTimeZone is the time zone of the absolute times on GeekNews pages (KST)
+var geekNewsTimeZone = time.FixedZone("KST", 9*60*60)
+
 // Article represents a news article from GeekNews
 type Article struct {
 	Title        string
-	Link         string // External article URL (may be empty if not available)
-	Comments     string // Comment count as string (empty for RSS-based list)
-	CommentsLink string // GeekNews topic URL
-	Domain       string // Extracted from Link or "news.hada.io" if Link is topic URL
+	Link         string    // External article URL (may be empty if not available)
+	Comments     string    // Comment count as string (empty for RSS-based list)
+	CommentsLink string    // GeekNews topic URL
+	Domain       string    // Extracted from Link or "news.hada.io" if Link is topic URL
+	Author       string    // GeekNews user who submitted the topic, as displayed
+	AuthorID     string    // Account ID of the author, which may differ from the name shown (GN⁺ is neo)
+	Points       string    // Points as string (empty for RSS-based list)
+	Posted       time.Time // Publication time (from the feed; zero if unknown)
+	Summary      string    // Topic text as plain text (from the feed; empty if unknown)
 }
 
 // Comment represents a comment from GeekNews
 type Comment struct {
-	Author string
-	Body   string // HTML converted to plain text
-	Depth  int    // Nesting level (0-based)
-	Time   string // Display as-is from GeekNews
-	ID     string // Comment ID
+	Author   string
+	AuthorID string // Account ID of the author
+	Body     string // HTML converted to plain text
+	Depth    int    // Nesting level (0-based)
+	Time     string // Display as-is from GeekNews
+	ID       string // Comment ID
 }
 
 // AtomFeed represents the GeekNews Atom feed structure
@@ -36,12 +46,13 @@ type AtomFeed struct {
 
 // AtomEntry represents a single entry in the Atom feed
 type AtomEntry struct {
-	Title   string     `xml:"title"`
-	Links   []AtomLink `xml:"link"`
-	ID      string     `xml:"id"`
-	Updated string     `xml:"updated"`
-	Author  AtomAuthor `xml:"author"`
-	Content string     `xml:"content"`
+	Title     string     `xml:"title"`
+	Links     []AtomLink `xml:"link"`
+	ID        string     `xml:"id"`
+	Updated   string     `xml:"updated"`
+	Published string     `xml:"published"`
+	Author    AtomAuthor `xml:"author"`
+	Content   string     `xml:"content"`
 }
 
 // AtomLink represents a link element in Atom
@@ -86,6 +97,15 @@ func parseGeekNewsRSS(xmlData string) ([]Article, error) {
 			Comments:     "", // Not available in RSS
 			CommentsLink: topicURL,
 			Domain:       "news.hada.io",
+			Author:       entry.Author.Name,
+			AuthorID:     extractUserID(entry.Author.URI),
+			Summary:      strings.TrimSpace(sanitize(entry.Content)),
+		}
+		for _, value := range []string{entry.Published, entry.Updated} {
+			if posted, err := time.Parse(time.RFC3339, strings.TrimSpace(value)); err == nil {
+				article.Posted = posted
+				break
+			}
 		}
 
 		articles = append(articles, article)
@@ -95,16 +115,18 @@ func parseGeekNewsRSS(xmlData string) ([]Article, error) {
 }
 
 // parseGeekNewsComments parses the GeekNews comments HTML and returns comments
-func parseGeekNewsComments(htmlContent string) ([]Comment, error) {
+func parseGeekNewsComments(htmlContent string) ([]Comment, *ParseReport, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	report := newParseReport()
 	var comments []Comment
 	depthRegex := regexp.MustCompile(`--depth:\s*(\d+)`)
 
-	doc.Find("#comment_thread .comment_row").Each(func(i int, s *goquery.Selection) {
+	report.find(doc.Selection, selCommentThread)
+	report.find(doc.Selection, selCommentRow).Each(func(i int, s *goquery.Selection) {
 		// Extract depth from style attribute
 		depth := 0
 		if style, exists := s.Attr("style"); exists {
@@ -113,17 +135,11 @@ func parseGeekNewsComments(htmlContent string) ([]Comment, error) {
 			}
 		}
 
-		// Limit depth to 10 as per spec
-		if depth > 10 {
-			// Skip deeply nested comments, will show [...] indicator
-			return
-		}
-
 		// Extract author
-		author := s.Find(".commentinfo a[href^='/user?id=']").First().Text()
+		authorSel := report.find(s, selCommentAuthor).First()
 
 		// Extract time
-		time := s.Find(".commentinfo a[href^='comment?id=']").Text()
+		time := report.find(s, selCommentTime).Text()
 
 		// Extract comment ID from element ID (e.g., "cid50523" -> "50523")
 		var commentID string
@@ -132,21 +148,22 @@ func parseGeekNewsComments(htmlContent string) ([]Comment, error) {
 		}
 
 		// Extract body and sanitize
-		bodyHTML, _ := s.Find(".commentTD .comment_contents").Html()
+		bodyHTML, _ := report.find(s, selCommentBody).Html()
 		body := sanitize(bodyHTML)
 
 		comment := Comment{
-			Author: author,
-			Body:   body,
-			Depth:  depth,
-			Time:   time,
-			ID:     commentID,
+			Author:   authorSel.Text(),
+			AuthorID: userIDOf(authorSel),
+			Body:     body,
+			Depth:    depth,
+			Time:     time,
+			ID:       commentID,
 		}
 
 		comments = append(comments, comment)
 	})
 
-	return comments, nil
+	return comments, report, nil
 }
 
 // TopicContent represents the parsed content of a GeekNews topic page
@@ -155,94 +172,263 @@ type TopicContent struct {
 	ExternalLink string
 	Body         string // Topic description/summary
 	Author       string
-	Time         string
+	AuthorID     string // Account ID of the author
+	Time         string // Relative time as displayed, e.g. "21시간전"
 	Points       string
+	Posted       time.Time // Posting time from the title of the time element; zero if missing
 }
 
 // parseGeekNewsTopicLink extracts the external article link from a topic page
-func parseGeekNewsTopicLink(htmlContent string) (string, string, error) {
+func parseGeekNewsTopicLink(htmlContent string) (string, string, *ParseReport, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
-		return "", "", err
+		return "", "", nil, err
 	}
 
-	// Try to find external link in topic header
-	// Selector: .topictitle.link > a or .topictitle > a
+	report := newParseReport()
 	var externalLink string
 	var title string
 
-	// First try .topictitle.link > a
-	linkSel := doc.Find(".topictitle.link > a").First()
+	linkSel := findTopicLink(doc, report)
 	if linkSel.Length() > 0 {
 		externalLink, _ = linkSel.Attr("href")
-		title = linkSel.Find("h1").Text()
+		title = report.find(linkSel, selTopicLinkTitle).Text()
 		if title == "" {
 			title = linkSel.Text()
 		}
 	}
 
-	// If not found, try alternative selectors
-	if externalLink == "" {
-		linkSel = doc.Find(".topictitle > a").First()
-		if linkSel.Length() > 0 {
-			externalLink, _ = linkSel.Attr("href")
-			title = linkSel.Find("h1").Text()
-			if title == "" {
-				title = linkSel.Text()
-			}
+	return externalLink, title, report, nil
+}
+
+// findTopicLink finds the anchor wrapping the topic title
+// Selector: .topictitle.link > a or .topictitle > a
+func findTopicLink(doc *goquery.Document, report *ParseReport) *goquery.Selection {
+	// First try .topictitle.link > a
+	linkSel := report.find(doc.Selection, selTopicLink).First()
+	if linkSel.Length() > 0 {
+		if href, _ := linkSel.Attr("href"); href != "" {
+			return linkSel
 		}
 	}
 
-	return externalLink, title, nil
+	// If not found, try alternative selectors
+	return report.find(doc.Selection, selTopicLinkFallback).First()
 }
 
 // parseGeekNewsTopicContent extracts the full topic content including body
-func parseGeekNewsTopicContent(htmlContent string) (*TopicContent, error) {
+func parseGeekNewsTopicContent(htmlContent string) (*TopicContent, *ParseReport, error) {
 	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
+	report := newParseReport()
 	content := &TopicContent{}
 
 	// Extract title
-	titleSel := doc.Find(".topictitle h1").First()
+	titleSel := report.find(doc.Selection, selTopicTitle).First()
 	if titleSel.Length() > 0 {
 		content.Title = strings.TrimSpace(titleSel.Text())
 	}
 
 	// Extract external link
-	linkSel := doc.Find(".topictitle.link > a, .topictitle > a").First()
+	linkSel := findTopicLink(doc, report)
 	if linkSel.Length() > 0 {
 		content.ExternalLink, _ = linkSel.Attr("href")
 	}
 
 	// Extract body from #topic_contents
-	bodySel := doc.Find("#topic_contents")
+	bodySel := report.find(doc.Selection, selTopicBody)
 	if bodySel.Length() > 0 {
 		bodyHTML, _ := bodySel.Html()
 		content.Body = sanitize(bodyHTML)
 	}
 
 	// Extract author
-	authorSel := doc.Find(".topicinfo a[href^='/user?id=']").First()
+	authorSel := report.find(doc.Selection, selTopicAuthor).First()
 	if authorSel.Length() > 0 {
 		content.Author = authorSel.Text()
+		content.AuthorID = userIDOf(authorSel)
 	}
 
 	// Extract time
-	timeSel := doc.Find(".topicinfo span[title]").First()
+	timeSel := report.find(doc.Selection, selTopicTime).First()
 	if timeSel.Length() > 0 {
 		content.Time = strings.TrimSpace(timeSel.Text())
+		if title, ok := timeSel.Attr("title"); ok {
+			content.Posted, _ = time.ParseInLocation("2006-01-02 15:04", strings.TrimSpace(title), geekNewsTimeZone)
+		}
 	}
 
 	// Extract points
-	pointsSel := doc.Find(".topicinfo span[id^='tp']").First()
+	pointsSel := report.find(doc.Selection, selTopicPoints).First()
 	if pointsSel.Length() > 0 {
 		content.Points = pointsSel.Text()
 	}
 
-	return content, nil
+	return content, report, nil
+}
+
+// parseGeekNewsTopicList parses a list of topic rows, as found on the homepage
+// and on a user's submitted topics page
+func parseGeekNewsTopicList(htmlContent string) ([]Article, *ParseReport, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := newParseReport()
+	var articles []Article
+	countRegex := regexp.MustCompile(`댓글\s*(\d+)`)
+
+	report.find(doc.Selection, selTopicRow).Each(func(i int, s *goquery.Selection) {
+		authorSel := report.find(s, selTopicAuthor).First()
+		article := Article{
+			Title:    strings.TrimSpace(report.find(s, selTopicTitle).First().Text()),
+			Author:   authorSel.Text(),
+			AuthorID: userIDOf(authorSel),
+			Points:   report.find(s, selTopicPoints).First().Text(),
+		}
+
+		// External article link; kept as-is since relative links mark posts without one (Ask GN)
+		if href, exists := report.find(s, selTopicLinkFallback).First().Attr("href"); exists {
+			article.Link = href
+		}
+
+		// Topic page link
+		if href, exists := report.find(s, selTopicRowTopic).First().Attr("href"); exists {
+			article.CommentsLink = resolveGeekNewsURL(href)
+		} else {
+			article.CommentsLink = article.Link
+		}
+
+		// Comment count text is either "댓글 N개" or "댓글과 토론" (no comments yet)
+		commentSel := report.find(s, selTopicRowComments).First()
+		if commentSel.Length() > 0 {
+			article.Comments = "0"
+			if matches := countRegex.FindStringSubmatch(commentSel.Text()); len(matches) > 1 {
+				article.Comments = matches[1]
+			}
+		}
+
+		article.Domain = extractDomainFromURL(article.Link)
+		if article.Domain == "" {
+			article.Domain = "news.hada.io"
+		}
+
+		articles = append(articles, article)
+	})
+
+	return articles, report, nil
+}
+
+// UserProfile represents a GeekNews user page
+type UserProfile struct {
+	ID      string
+	Karma   string
+	Created string // Join date, displayed as-is
+	About   string
+}
+
+// UserComment is a comment listed on a user's comments page, with the topic it belongs to
+type UserComment struct {
+	Comment
+	TopicLink  string
+	TopicTitle string
+}
+
+// parseGeekNewsUserProfile parses a GeekNews user page (/user?id=...).
+// Profile fields are label/value table rows; labels are matched in Korean or English.
+func parseGeekNewsUserProfile(htmlContent string) (*UserProfile, *ParseReport, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := newParseReport()
+	profile := &UserProfile{}
+
+	report.find(doc.Selection, selUserRow).Each(func(i int, s *goquery.Selection) {
+		cells := s.Children()
+		if cells.Length() < 2 {
+			return
+		}
+		label := strings.ToLower(strings.TrimSpace(cells.First().Text()))
+		label = strings.TrimSuffix(label, ":")
+		valueHTML, _ := cells.Eq(1).Html()
+		value := strings.TrimSpace(sanitize(valueHTML))
+
+		switch {
+		case label == "id" || label == "user" || strings.Contains(label, "아이디"):
+			profile.ID = value
+		case strings.Contains(label, "karma") || strings.Contains(label, "카르마"):
+			profile.Karma = value
+		case strings.Contains(label, "created") || strings.Contains(label, "가입"):
+			profile.Created = value
+		case strings.Contains(label, "about") || strings.Contains(label, "소개"):
+			profile.About = value
+		}
+	})
+
+	return profile, report, nil
+}
+
+// parseGeekNewsUserComments parses a user's comments page
+func parseGeekNewsUserComments(htmlContent string) ([]UserComment, *ParseReport, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	report := newParseReport()
+	var comments []UserComment
+
+	report.find(doc.Selection, selUserCommentRow).Each(func(i int, s *goquery.Selection) {
+		var comment UserComment
+		comment.Author = report.find(s, selCommentAuthor).First().Text()
+		comment.Time = report.find(s, selCommentTime).First().Text()
+		if id, exists := s.Attr("id"); exists {
+			comment.ID = strings.TrimPrefix(id, "cid")
+		}
+		bodyHTML, _ := report.find(s, selCommentBody).Html()
+		comment.Body = sanitize(bodyHTML)
+
+		topicSel := report.find(s, selUserCommentTopic).First()
+		if href, exists := topicSel.Attr("href"); exists {
+			comment.TopicLink = resolveGeekNewsURL(href)
+			comment.TopicTitle = strings.TrimSpace(topicSel.Text())
+		}
+
+		comments = append(comments, comment)
+	})
+
+	return comments, report, nil
+}
+
+// parseGeekNewsCommentTopic extracts the URL of the topic a comment page belongs to
+func parseGeekNewsCommentTopic(htmlContent string) (string, *ParseReport, error) {
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(htmlContent))
+	if err != nil {
+		return "", nil, err
+	}
+
+	report := newParseReport()
+	href, _ := report.find(doc.Selection, selCommentPageTopic).First().Attr("href")
+	if href == "" {
+		return "", report, nil
+	}
+	return resolveGeekNewsURL(href), report, nil
+}
+
+// resolveGeekNewsURL turns a link relative to news.hada.io into an absolute URL
+func resolveGeekNewsURL(href string) string {
+	base, _ := url.Parse(geekNewsBaseURL)
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return base.ResolveReference(ref).String()
 }
 
 // extractDomainFromURL extracts the domain from a URL
@@ -257,6 +443,34 @@ func extractDomainFromURL(link string) string {
 	return u.Host
 }
 
+// userIDOf returns the account ID from the /user?id= link of an author
+func userIDOf(sel *goquery.Selection) string {
+	href, _ := sel.Attr("href")
+	return extractUserID(resolveGeekNewsURL(href))
+}
+
+// extractUserID extracts the account ID from a GeekNews user URL, or returns ""
+func extractUserID(userURL string) string {
+	u, err := url.Parse(userURL)
+	if err != nil || strings.TrimPrefix(u.Path, "/") != "user" {
+		return ""
+	}
+	return u.Query().Get("id")
+}
+
+// isTopicID reports whether id is a GeekNews topic ID, which is all digits
+func isTopicID(id string) bool {
+	if id == "" {
+		return false
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // extractTopicID extracts the topic ID from a GeekNews URL
 func extractTopicID(topicURL string) string {
 	u, err := url.Parse(topicURL)
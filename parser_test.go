@@ -34,6 +34,9 @@ func TestParseGeekNewsRSS(t *testing.T) {
 	if articles[0].Domain != "news.hada.io" {
 		t.Errorf("Expected Domain 'news.hada.io', got %q", articles[0].Domain)
 	}
+	if articles[0].Author != "davespark" || articles[0].AuthorID != "davespark" {
+		t.Errorf("Expected Author 'davespark', got %q (%q)", articles[0].Author, articles[0].AuthorID)
+	}
 
 	// Test second article
 	if articles[1].Title != "Todd C. Miller – 30년 넘게 Sudo를 유지보수한 개발자" {
@@ -75,7 +78,7 @@ func TestParseGeekNewsComments(t *testing.T) {
 		t.Fatalf("Failed to read test fixture: %v", err)
 	}
 
-	comments, err := parseGeekNewsComments(string(htmlContent))
+	comments, _, err := parseGeekNewsComments(string(htmlContent))
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -86,8 +89,8 @@ func TestParseGeekNewsComments(t *testing.T) {
 
 	// Test first comment (depth 0)
 	firstComment := comments[0]
-	if firstComment.Author != "kuthia" {
-		t.Errorf("Expected author 'kuthia', got %q", firstComment.Author)
+	if firstComment.Author != "kuthia" || firstComment.AuthorID != "kuthia" {
+		t.Errorf("Expected author 'kuthia', got %q (%q)", firstComment.Author, firstComment.AuthorID)
 	}
 	if firstComment.Depth != 0 {
 		t.Errorf("Expected depth 0, got %d", firstComment.Depth)
@@ -118,7 +121,7 @@ func TestParseGeekNewsComments(t *testing.T) {
 func TestParseGeekNewsComments_Empty(t *testing.T) {
 	emptyHTML := `<div id='comment_thread' class='comment_thread'></div>`
 
-	comments, err := parseGeekNewsComments(emptyHTML)
+	comments, _, err := parseGeekNewsComments(emptyHTML)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -155,7 +158,7 @@ func TestParseGeekNewsTopicContent(t *testing.T) {
 		t.Fatalf("Failed to read test fixture: %v", err)
 	}
 
-	content, err := parseGeekNewsTopicContent(string(htmlContent))
+	content, _, err := parseGeekNewsTopicContent(string(htmlContent))
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -199,7 +202,7 @@ func TestParseGeekNewsTopicContent_NoBody(t *testing.T) {
 	// Test with minimal HTML (no topic_contents)
 	minimalHTML := `<div class="topictitle"><a href="https://example.com"><h1>Test Title</h1></a></div>`
 
-	content, err := parseGeekNewsTopicContent(minimalHTML)
+	content, _, err := parseGeekNewsTopicContent(minimalHTML)
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
@@ -232,3 +235,120 @@ func TestExtractDomainFromURL(t *testing.T) {
 		}
 	}
 }
+
+func TestParseGeekNewsTopicList(t *testing.T) {
+	htmlContent, err := os.ReadFile("testdata/geeknews_homepage_topics.html")
+	if err != nil {
+		t.Fatalf("Failed to read test fixture: %v", err)
+	}
+
+	articles, report, err := parseGeekNewsTopicList(string(htmlContent))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(articles) != 20 {
+		t.Fatalf("Expected 20 articles, got %d", len(articles))
+	}
+	if missing := report.MissingRequired(); len(missing) != 0 {
+		t.Errorf("Expected no missing required selectors, got %v", missing)
+	}
+
+	first := articles[0]
+	if first.Title != "AI 코딩 도구가 개발자 학습을 방해한다, Anthropic 연구 발견" {
+		t.Errorf("Unexpected title %q", first.Title)
+	}
+	if first.Link != "https://www.anthropic.com/research/AI-assistance-coding-skills" {
+		t.Errorf("Unexpected link %q", first.Link)
+	}
+	if first.CommentsLink != "https://news.hada.io/topic?id=26364" {
+		t.Errorf("Unexpected comments link %q", first.CommentsLink)
+	}
+	if first.Domain != "www.anthropic.com" {
+		t.Errorf("Unexpected domain %q", first.Domain)
+	}
+	if first.Author != "davespark" || first.Points != "5" || first.Comments != "2" {
+		t.Errorf("Unexpected meta author=%q points=%q comments=%q", first.Author, first.Points, first.Comments)
+	}
+
+	// GN⁺ posts as the account neo; the profile is looked up by the account
+	var gn *Article
+	for i := range articles {
+		if extractTopicID(articles[i].CommentsLink) == "26359" {
+			gn = &articles[i]
+		}
+	}
+	if gn == nil || gn.Author != "GN⁺" || gn.AuthorID != "neo" {
+		t.Errorf("Expected topic 26359 by GN⁺ (neo), got %+v", gn)
+	}
+	if first.AuthorID != "davespark" {
+		t.Errorf("Expected author ID davespark, got %q", first.AuthorID)
+	}
+
+	// "댓글과 토론" means no comments yet
+	foundZero := false
+	for _, article := range articles {
+		if article.Comments == "0" {
+			foundZero = true
+		}
+	}
+	if !foundZero {
+		t.Error("Expected an article without comments to have count '0'")
+	}
+}
+
+func TestParseGeekNewsUserProfile(t *testing.T) {
+	htmlContent, err := os.ReadFile("testdata/geeknews_user.html")
+	if err != nil {
+		t.Fatalf("Failed to read test fixture: %v", err)
+	}
+
+	profile, report, err := parseGeekNewsUserProfile(string(htmlContent))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if profile.ID != "gracefullight" {
+		t.Errorf("Expected ID 'gracefullight', got %q", profile.ID)
+	}
+	if profile.Karma != "1042" {
+		t.Errorf("Expected karma '1042', got %q", profile.Karma)
+	}
+	if profile.Created != "2021-03-14" {
+		t.Errorf("Expected join date '2021-03-14', got %q", profile.Created)
+	}
+	if !strings.Contains(profile.About, "에이전트 도구") {
+		t.Errorf("Expected about text, got %q", profile.About)
+	}
+	if missing := report.MissingRequired(); len(missing) != 0 {
+		t.Errorf("Expected no missing required selectors, got %v", missing)
+	}
+}
+
+func TestParseGeekNewsUserComments(t *testing.T) {
+	htmlContent, err := os.ReadFile("testdata/geeknews_user_comments.html")
+	if err != nil {
+		t.Fatalf("Failed to read test fixture: %v", err)
+	}
+
+	comments, _, err := parseGeekNewsUserComments(string(htmlContent))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(comments) != 2 {
+		t.Fatalf("Expected 2 comments, got %d", len(comments))
+	}
+	if comments[0].ID != "50528" || comments[0].Time != "5시간전" {
+		t.Errorf("Unexpected comment id=%q time=%q", comments[0].ID, comments[0].Time)
+	}
+	if comments[0].TopicLink != "https://news.hada.io/topic?id=26307" {
+		t.Errorf("Unexpected topic link %q", comments[0].TopicLink)
+	}
+	if !strings.HasPrefix(comments[0].TopicTitle, "Show GN: oh-my-ag") {
+		t.Errorf("Unexpected topic title %q", comments[0].TopicTitle)
+	}
+	if !strings.Contains(comments[1].Body, "iterator") {
+		t.Errorf("Unexpected body %q", comments[1].Body)
+	}
+}
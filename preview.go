@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+const (
+	previewDelay    = 250 * time.Millisecond // Wait for the cursor to settle before loading a preview
+	previewComments = 3                      // Top-level comments shown in the preview
+)
+
+// previewPane shows the topic selected in the article list next to it on wide terminals
+type previewPane struct {
+	page    *textPage
+	split   *tview.Flex // Article list and preview side by side
+	enabled bool        // Toggled by the user; the pane also needs split_min_width columns
+	visible bool
+	topicID string // Topic shown or being loaded
+	timer   *time.Timer
+}
+
+// splitLayout draws the article list and preview, hiding the preview when too narrow
+type splitLayout struct {
+	*tview.Flex
+	s *session
+}
+
+func (l *splitLayout) Draw(screen tcell.Screen) {
+	_, _, width, _ := l.GetRect()
+	l.s.layoutPreview(width)
+	l.Flex.Draw(screen)
+}
+
+// newPreviewPane creates the split layout around list
+func (s *session) newPreviewPane() *previewPane {
+	pv := &previewPane{enabled: true}
+	pv.page = newTextPage(staticText(""))
+	pv.page.view.SetBorder(true).SetTitle(" 미리보기 ")
+	pv.page.textMouse(true, s.followLink, nil)
+	pv.split = tview.NewFlex().
+		AddItem(s.list, 0, 2, true).
+		AddItem(pv.page.layout, 0, 0, false)
+
+	s.list.SetChangedFunc(func(index int, _, _ string, _ rune) {
+		s.schedulePreview()
+	})
+	return pv
+}
+
+// layoutPreview shows or hides the preview for a home layout width columns wide
+func (s *session) layoutPreview(width int) {
+	pv := s.preview
+	visible := pv.enabled && width >= settings.SplitMinWidth && settings.SplitMinWidth > 0
+	if visible == pv.visible {
+		return
+	}
+	pv.visible = visible
+	if visible {
+		pv.split.ResizeItem(pv.page.layout, 0, 3)
+		pv.topicID = ""
+		s.schedulePreview()
+	} else {
+		pv.split.ResizeItem(pv.page.layout, 0, 0)
+	}
+}
+
+// togglePreview turns the preview pane on or off
+func (s *session) togglePreview() {
+	s.preview.enabled = !s.preview.enabled
+	switch {
+	case !s.preview.enabled:
+		s.setStatus("미리보기 끔")
+	case settings.SplitMinWidth == 0:
+		s.setStatus("미리보기 켬 (split_min_width가 0이라 표시되지 않습니다)")
+	default:
+		s.setStatus(fmt.Sprintf("미리보기 켬 (%d칸 이상에서 표시)", settings.SplitMinWidth))
+	}
+}
+
+// schedulePreview shows the selected topic in the preview, loading its page once the
+// cursor has rested on it for previewDelay
+func (s *session) schedulePreview() {
+	pv := s.preview
+	if pv == nil || !pv.visible {
+		return
+	}
+	article, ok := s.listArticle(s.list.GetCurrentItem())
+	if !ok {
+		return
+	}
+	topicID := extractTopicID(article.CommentsLink)
+	if topicID == pv.topicID {
+		return
+	}
+	pv.topicID = topicID
+
+	if pv.timer != nil {
+		pv.timer.Stop()
+	}
+	pv.page.setRender(func(width int) ([]string, []int) {
+		return formatPreview(article, nil, "불러오는 중...", width), nil
+	})
+	if topicID == "" {
+		return
+	}
+
+	pv.timer = time.AfterFunc(previewDelay, func() {
+		page, err := fetchTopicPage(topicID)
+		s.app.QueueUpdateDraw(func() {
+			if pv.topicID != topicID {
+				return // The cursor moved on
+			}
+			message := ""
+			if err != nil {
+				message = paint(theme.Error, "페이지를 불러오는데 실패했습니다: "+tview.Escape(err.Error()))
+			}
+			pv.page.setRender(func(width int) ([]string, []int) {
+				return formatPreview(article, page, message, width), nil
+			})
+		})
+	})
+}
+
+// setRender replaces the text of the page, laying it out at the next draw
+func (p *textPage) setRender(render renderFunc) {
+	p.render = render
+	p.width = 0
+	p.anchors = nil
+	p.view.ScrollToBeginning()
+}
+
+// formatPreview formats the preview of article: its title and metadata, then, once page has
+// loaded, the topic summary and first comments. message is shown instead while page is nil.
+func formatPreview(article Article, page *TopicPage, message string, width int) []string {
+	var lines []string
+	lines = append(lines, wrapTextWithRuneWidth(tview.Escape(article.Title), width, "")...)
+	for i := range lines {
+		lines[i] = paint(theme.Title, lines[i])
+	}
+
+	meta := []string{article.Domain}
+	if article.Author != "" {
+		meta = append(meta, article.Author)
+	}
+	if article.Points != "" {
+		meta = append(meta, article.Points+"P")
+	}
+	if page != nil {
+		meta = append(meta, fmt.Sprintf("댓글 %d개", len(page.Comments)))
+	}
+	lines = append(lines, paint(theme.Meta, tview.Escape(strings.Join(meta, " · "))), "")
+
+	if page == nil {
+		return append(lines, message)
+	}
+	if warning := layoutWarning(page.Report); warning != "" {
+		lines = append(lines, warning, "")
+	}
+
+	if page.Content != nil && page.Content.Body != "" {
+		body := *page.Content
+		body.Title, body.Author, body.Time, body.Points = "", "", "", ""
+		lines = append(lines, formatTopicContent(&body, width)...)
+	}
+
+	if len(page.Comments) == 0 {
+		return append(lines, "아직 댓글이 없습니다.")
+	}
+	lines = append(lines, strings.Repeat("─", width), "")
+
+	op := ""
+	if page.Content != nil {
+		op = page.Content.Author
+	}
+	shown := 0
+	for _, comment := range page.Comments {
+		if comment.Depth > 0 {
+			continue
+		}
+		if shown == previewComments {
+			break
+		}
+		lines = append(lines, formatComment(comment, op, width)...)
+		shown++
+	}
+	if more := len(page.Comments) - shown; more > 0 {
+		lines = append(lines, paint(theme.Meta, fmt.Sprintf("… 댓글 %d개 더", more)))
+	}
+	return lines
+}
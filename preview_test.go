@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFormatPreview(t *testing.T) {
+	article := Article{Title: "미리보기 제목", Domain: "example.com", Points: "12"}
+
+	loading := strings.Join(formatPreview(article, nil, "불러오는 중...", 40), "\n")
+	for _, want := range []string{"미리보기 제목", "example.com · 12P", "불러오는 중..."} {
+		if !strings.Contains(loading, want) {
+			t.Errorf("Expected loading preview to contain %q, got %q", want, loading)
+		}
+	}
+
+	page := &TopicPage{
+		Content: &TopicContent{Title: "미리보기 제목", Author: "op", Body: "요약 본문입니다."},
+		Report:  newParseReport(),
+	}
+	for i := 0; i < 5; i++ {
+		page.Comments = append(page.Comments,
+			Comment{Author: fmt.Sprintf("top%d", i), Body: "댓글"},
+			Comment{Author: fmt.Sprintf("reply%d", i), Body: "답글", Depth: 1})
+	}
+
+	text := strings.Join(formatPreview(article, page, "", 40), "\n")
+	for _, want := range []string{"댓글 10개", "요약 본문입니다.", "top0", "top2", "댓글 7개 더"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected preview to contain %q, got %q", want, text)
+		}
+	}
+	for _, unwanted := range []string{"top3", "reply0"} {
+		if strings.Contains(text, unwanted) {
+			t.Errorf("Expected preview to show only the first top-level comments, found %q", unwanted)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
+)
+
+// readLogKeep is how long opened topics and read articles are remembered
+const readLogKeep = 90 * 24 * time.Hour
+
+// readLog remembers, by topic ID, the topics whose comments were opened and the
+// articles that were read, with when that happened
+type readLog struct {
+	Opened   map[string]time.Time `json:"opened"` // First opened, or when comments were last marked seen
+	Read     map[string]time.Time `json:"read"`
+	Comments map[string][]string  `json:"comments"` // IDs of the comments seen, by topic ID
+
+	path string // File the log is saved to; not saved if empty
+}
+
+// reads is the read log, set up by main. Without it nothing is remembered.
+var reads *readLog
+
+func newReadLog(path string) *readLog {
+	return &readLog{
+		Opened:   make(map[string]time.Time),
+		Read:     make(map[string]time.Time),
+		Comments: make(map[string][]string),
+		path:     path,
+	}
+}
+
+// readLogPath returns the path of the read log file
+func readLogPath() (string, error) {
+	return statePath("read.json")
+}
+
+// loadReadLog reads the log saved at path, forgetting topics older than readLogKeep
+func loadReadLog(path string) (*readLog, error) {
+	l := newReadLog(path)
+	if err := loadState(path, l); err != nil {
+		return nil, err
+	}
+	if l.Opened == nil {
+		l.Opened = make(map[string]time.Time)
+	}
+	if l.Read == nil {
+		l.Read = make(map[string]time.Time)
+	}
+	if l.Comments == nil {
+		l.Comments = make(map[string][]string)
+	}
+
+	cutoff := time.Now().Add(-readLogKeep)
+	for _, times := range []map[string]time.Time{l.Opened, l.Read} {
+		for id, t := range times {
+			if t.Before(cutoff) {
+				delete(times, id)
+			}
+		}
+	}
+	for id := range l.Comments {
+		if !l.isOpened(id) {
+			delete(l.Comments, id)
+		}
+	}
+	return l, nil
+}
+
+// isOpened reports whether the comments of the topic were opened
+func (l *readLog) isOpened(topicID string) bool {
+	return l != nil && !l.Opened[topicID].IsZero()
+}
+
+// isRead reports whether the article of the topic was read
+func (l *readLog) isRead(topicID string) bool {
+	return l != nil && !l.Read[topicID].IsZero()
+}
+
+// seen reports whether the topic was opened or its article read
+func (l *readLog) seen(topicID string) bool {
+	return l.isOpened(topicID) || l.isRead(topicID)
+}
+
+// markOpened records that the comments of the topics were opened
+func (l *readLog) markOpened(topicIDs ...string) error {
+	if l == nil {
+		return nil
+	}
+	return l.mark(l.Opened, topicIDs)
+}
+
+// markRead records that the article of the topic was read
+func (l *readLog) markRead(topicID string) error {
+	if l == nil {
+		return nil
+	}
+	return l.mark(l.Read, []string{topicID})
+}
+
+// mark adds the topics not in times yet and saves the log if that changed it
+func (l *readLog) mark(times map[string]time.Time, topicIDs []string) error {
+	now := time.Now()
+	changed := false
+	for _, id := range topicIDs {
+		if id != "" && times[id].IsZero() {
+			times[id] = now
+			changed = true
+		}
+	}
+	if !changed || l.path == "" {
+		return nil
+	}
+	return saveState(l.path, l)
+}
+
+// unseenComments returns the IDs of the comments of the topic that were not there on an
+// earlier visit. On the first visit no comment is new.
+func (l *readLog) unseenComments(topicID string, comments []Comment) map[string]bool {
+	if l == nil {
+		return nil
+	}
+	seen, ok := l.Comments[topicID]
+	if !ok {
+		return nil
+	}
+	known := make(map[string]bool, len(seen))
+	for _, id := range seen {
+		known[id] = true
+	}
+	unseen := make(map[string]bool)
+	for _, comment := range comments {
+		if comment.ID != "" && !known[comment.ID] {
+			unseen[comment.ID] = true
+		}
+	}
+	return unseen
+}
+
+// markComments records the comments of the topic as seen. It also refreshes when the
+// topic was opened, so that a topic still being followed keeps its comments past readLogKeep.
+func (l *readLog) markComments(topicID string, comments []Comment) error {
+	if l == nil || topicID == "" {
+		return nil
+	}
+	seen := l.Comments[topicID]
+	known := make(map[string]bool, len(seen))
+	for _, id := range seen {
+		known[id] = true
+	}
+	for _, comment := range comments {
+		if comment.ID != "" && !known[comment.ID] {
+			known[comment.ID] = true
+			seen = append(seen, comment.ID)
+		}
+	}
+	l.Opened[topicID] = time.Now()
+	if seen == nil {
+		seen = []string{} // Visited without comments: later ones are new
+	}
+	l.Comments[topicID] = seen
+	if l.path == "" {
+		return nil
+	}
+	return saveState(l.path, l)
+}
+
+// listItem returns the list texts of article: the title, dimmed once the topic was
+// opened, and the domain, marked once the article was read
+func listItem(article Article) (mainText, secondaryText string) {
+	topicID := extractTopicID(article.CommentsLink)
+	mainText = tview.Escape(article.Title)
+	if reads.seen(topicID) {
+		mainText = paint(theme.Read, mainText)
+	}
+	secondaryText = tview.Escape(article.Domain)
+	if reads.isRead(topicID) {
+		secondaryText += " · 읽음"
+	}
+	return mainText, secondaryText
+}
+
+// markOpened records that the comments of article were opened and dims it in the list
+func (s *session) markOpened(article Article) {
+	s.saveReads(reads.markOpened(extractTopicID(article.CommentsLink)))
+	s.updateListItem(article)
+}
+
+// markRead records that the article was read and marks it in the list
+func (s *session) markRead(article Article) {
+	s.saveReads(reads.markRead(extractTopicID(article.CommentsLink)))
+	s.updateListItem(article)
+}
+
+func (s *session) saveReads(err error) {
+	if err != nil {
+		s.setStatus(paint(theme.Error, "읽음 기록을 저장하지 못했습니다: "+tview.Escape(err.Error())))
+	}
+}
+
+// updateListItem restyles the list row of article, if it is shown, and the unread count
+func (s *session) updateListItem(article Article) {
+	for row, index := range s.rows {
+		if s.articles[index].CommentsLink == article.CommentsLink {
+			mainText, secondaryText := listItem(s.articles[index])
+			s.list.SetItemText(row, mainText, secondaryText)
+		}
+	}
+	s.updateCounter()
+}
+
+// toggleHideRead hides or shows the articles that were opened or read
+func (s *session) toggleHideRead() {
+	index := s.currentIndex()
+	s.hideRead = !s.hideRead
+	s.fillList()
+	s.selectIndex(index)
+	if s.hideRead {
+		s.setStatus("읽은 글 숨김")
+	} else {
+		s.setStatus("읽은 글 표시")
+	}
+}
+
+// markAllRead marks every article in the list as opened, only the matching ones while filtering
+func (s *session) markAllRead() {
+	var topicIDs []string
+	for _, index := range s.rows {
+		topicIDs = append(topicIDs, extractTopicID(s.articles[index].CommentsLink))
+	}
+	index := s.currentIndex()
+	if err := reads.markOpened(topicIDs...); err != nil {
+		s.saveReads(err)
+	} else {
+		s.setStatus(fmt.Sprintf("%d개 글을 읽음으로 표시했습니다", len(topicIDs)))
+	}
+	s.fillList()
+	s.selectIndex(index)
+}
+
+// unreadCount returns the number of articles neither opened nor read
+func (s *session) unreadCount() int {
+	count := 0
+	for _, article := range s.articles {
+		if !reads.seen(extractTopicID(article.CommentsLink)) {
+			count++
+		}
+	}
+	return count
+}
+
+// updateCounter shows the number of unread articles at the right of the status bar
+func (s *session) updateCounter() {
+	text := fmt.Sprintf("안 읽음 %d/%d", s.unreadCount(), len(s.articles))
+	if s.hideRead {
+		text += " · 읽은 글 숨김"
+	}
+	s.counter.SetText(text)
+	s.statusBar.ResizeItem(s.counter, runewidth.StringWidth(text)+1, 0)
+}
+
+// nextNewComment scrolls the comments page to the next comment posted since the last visit,
+// expanding the folded replies hiding it, and back to the first after the last one
+func (s *session) nextNewComment() {
+	p := s.textPages["comments"]
+	page := s.page
+	if p == nil || page == nil || len(page.unseen) == 0 {
+		s.setStatus("새 댓글이 없습니다")
+		return
+	}
+
+	row, _ := p.view.GetScrollOffset()
+	for relaid := false; ; {
+		blocks := page.blocks()
+		base := len(p.anchors) - len(blocks) // Anchors before the comments: the topic
+		if base < 0 {
+			// Not laid out yet, or comments were added since: lay the page out once more
+			if relaid {
+				return
+			}
+			p.relayout()
+			relaid = true
+			continue
+		}
+		first, next := -1, -1
+		for k, block := range blocks {
+			if page.unseenIn(block) == 0 {
+				continue
+			}
+			if first < 0 {
+				first = k
+			}
+			if p.anchors[base+k] > row {
+				next = k
+				break
+			}
+		}
+		if next < 0 {
+			next = first
+		}
+
+		block := blocks[next]
+		if block.folded {
+			// Expand the replies and look again from just above them
+			page.expandFold(block.start)
+			p.relayout()
+			relaid = true
+			if base+next >= len(p.anchors) {
+				return
+			}
+			row = p.anchors[base+next] - 1
+			continue
+		}
+		p.view.ScrollTo(p.anchors[base+next], 0)
+
+		position := 0
+		for _, comment := range page.Comments[:block.end] {
+			if page.unseen[comment.ID] {
+				position++
+			}
+		}
+		s.setStatus(fmt.Sprintf("새 댓글 %d/%d", position, len(page.unseen)))
+		return
+	}
+}
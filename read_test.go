@@ -0,0 +1,230 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rivo/tview"
+)
+
+func TestReadLogPersists(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "state", "read.json")
+	l, err := loadReadLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error for a missing log: %v", err)
+	}
+	if err := l.markOpened("1", "2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := l.markRead("2"); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	l, err = loadReadLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !l.isOpened("1") || !l.isOpened("2") || l.isRead("1") || !l.isRead("2") || l.seen("3") {
+		t.Errorf("Unexpected log after reload: %+v", l)
+	}
+}
+
+func TestReadLogForgetsOldTopics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "read.json")
+	old := time.Now().Add(-readLogKeep - time.Hour).Format(time.RFC3339)
+	recent := time.Now().Format(time.RFC3339)
+	data := `{"opened": {"1": "` + old + `", "2": "` + recent + `"}, "read": null}`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	l, err := loadReadLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if l.isOpened("1") || !l.isOpened("2") {
+		t.Errorf("Expected only the recent topic to be kept, got %v", l.Opened)
+	}
+	if err := l.markRead("2"); err != nil || !l.isRead("2") {
+		t.Errorf("Expected to mark a read article after loading a null map, got %v", err)
+	}
+}
+
+func TestHideReadArticles(t *testing.T) {
+	saved := reads
+	reads = newReadLog("")
+	defer func() { reads = saved }()
+
+	var articles []Article
+	for _, id := range []string{"1", "2", "3", "4"} {
+		articles = append(articles, Article{Title: "글 " + id, CommentsLink: geekNewsBaseURL + "topic?id=" + id})
+	}
+	reads.markOpened("2")
+	reads.markRead("4")
+
+	s := newSession(tview.NewApplication(), articles, defaultKeymap())
+	if got := s.counter.GetText(true); got != "안 읽음 2/4" {
+		t.Errorf("Expected unread count 2/4, got %q", got)
+	}
+	if mainText, secondaryText := s.list.GetItemText(3); !strings.Contains(mainText, theme.Read) || !strings.HasSuffix(secondaryText, "읽음") {
+		t.Errorf("Expected the read article dimmed and marked, got %q, %q", mainText, secondaryText)
+	}
+
+	s.list.SetCurrentItem(1)
+	s.toggleHideRead()
+	if s.list.GetItemCount() != 2 {
+		t.Fatalf("Expected 2 unread articles shown, got %d", s.list.GetItemCount())
+	}
+	if article, _ := s.listArticle(s.list.GetCurrentItem()); article.Title != "글 3" {
+		t.Errorf("Expected the next unread article selected, got %q", article.Title)
+	}
+
+	s.markAllRead()
+	if s.list.GetItemCount() != 0 || s.unreadCount() != 0 {
+		t.Errorf("Expected every article read and hidden, got %d shown, %d unread", s.list.GetItemCount(), s.unreadCount())
+	}
+
+	s.toggleHideRead()
+	if s.list.GetItemCount() != 4 {
+		t.Errorf("Expected every article shown again, got %d", s.list.GetItemCount())
+	}
+}
+
+func TestUnseenComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "read.json")
+	l := newReadLog(path)
+	first := []Comment{{ID: "1"}, {ID: "2"}, {ID: ""}}
+	if unseen := l.unseenComments("9", first); len(unseen) != 0 {
+		t.Errorf("Expected no new comments on the first visit, got %v", unseen)
+	}
+	l.markOpened("9")
+	if err := l.markComments("9", first); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	l, err := loadReadLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second := []Comment{{ID: "1"}, {ID: "3"}, {ID: "2"}, {ID: "4"}}
+	unseen := l.unseenComments("9", second)
+	if len(unseen) != 2 || !unseen["3"] || !unseen["4"] {
+		t.Errorf("Expected comments 3 and 4 to be new, got %v", unseen)
+	}
+
+	l.markComments("9", second)
+	if unseen := l.unseenComments("9", second); len(unseen) != 0 {
+		t.Errorf("Expected no new comments after marking them seen, got %v", unseen)
+	}
+
+	// A topic visited without comments gets new comments later
+	l.markComments("10", nil)
+	if unseen := l.unseenComments("10", []Comment{{ID: "5"}}); !unseen["5"] {
+		t.Errorf("Expected the first comment of an empty topic to be new, got %v", unseen)
+	}
+}
+
+func TestReadLogKeepsFollowedTopics(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "read.json")
+	l := newReadLog(path)
+	first := time.Now().Add(-readLogKeep - time.Hour)
+	l.Opened["9"], l.Opened["10"] = first, first
+	l.Comments["9"], l.Comments["10"] = []string{"1"}, []string{"1"}
+
+	// Topic 9 is still followed; topic 10 was not visited again
+	if err := l.markComments("9", []Comment{{ID: "1"}, {ID: "2"}}); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	l, err := loadReadLog(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(l.Comments["9"]) != 2 || !l.isOpened("9") {
+		t.Errorf("Expected the followed topic to keep its comments, got %v", l.Comments["9"])
+	}
+	if _, ok := l.Comments["10"]; ok || l.isOpened("10") {
+		t.Error("Expected the topic not visited again to be forgotten")
+	}
+}
+
+func TestNextNewComment(t *testing.T) {
+	saved := theme
+	theme = Theme{New: "lime"}
+	defer func() { theme = saved }()
+
+	limit := settings.CommentDepthLimit
+	page := &TopicPage{
+		Content: &TopicContent{Title: "제목", Body: "본문"},
+		Comments: []Comment{
+			{ID: "1", Author: "a", Body: "old"},
+			{ID: "2", Author: "b", Body: "new"},
+			{ID: "3", Author: "c", Body: "old"},
+			{ID: "4", Author: "d", Body: "deep old", Depth: limit + 1},
+			{ID: "5", Author: "e", Body: "deep new", Depth: limit + 1},
+		},
+		unseen: map[string]bool{"2": true, "5": true},
+	}
+
+	lines, _ := layoutTopicPage(page, 60)
+	text := strings.Join(lines, "\n")
+	for _, want := range []string{"[lime]새 댓글 2개[-:-:-]", "b 님: [lime]" + newBadge, "답글 2개 더 보기 [lime]새 댓글 1개"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Expected %q in %q", want, text)
+		}
+	}
+
+	s := newSession(tview.NewApplication(), nil, defaultKeymap())
+	s.page = page
+	p := newTextPage(func(width int) ([]string, []int) { return layoutTopicPage(page, width) })
+	p.view.SetRect(0, 0, 60, 5)
+	s.textPages["comments"] = p
+	p.reflow(60)
+
+	lineAt := func() string {
+		row, _ := p.view.GetScrollOffset()
+		return strings.Split(p.view.GetText(true), "\n")[row]
+	}
+
+	s.nextNewComment()
+	if !strings.HasPrefix(strings.TrimLeft(lineAt(), " |"), "b 님:") {
+		t.Errorf("Expected to jump to comment 2, at %q", lineAt())
+	}
+	s.nextNewComment()
+	if !strings.HasPrefix(strings.TrimLeft(lineAt(), " |"), "e 님:") || len(page.foldedRuns()) != 0 {
+		t.Errorf("Expected to expand the fold and jump to comment 5, at %q", lineAt())
+	}
+	if got := s.status.GetText(true); got != "새 댓글 2/2" {
+		t.Errorf("Expected status 새 댓글 2/2, got %q", got)
+	}
+	s.nextNewComment()
+	if !strings.HasPrefix(strings.TrimLeft(lineAt(), " |"), "b 님:") {
+		t.Errorf("Expected to wrap around to comment 2, at %q", lineAt())
+	}
+}
+
+func TestNextNewCommentBeforeLayout(t *testing.T) {
+	page := &TopicPage{
+		Content:  &TopicContent{Title: "제목"},
+		Comments: []Comment{{ID: "1", Author: "a", Body: "new"}, {ID: "2", Author: "b", Body: "new"}},
+		unseen:   map[string]bool{"1": true, "2": true},
+	}
+	s := newSession(tview.NewApplication(), nil, defaultKeymap())
+	s.page = page
+	p := newTextPage(func(width int) ([]string, []int) { return layoutTopicPage(page, width) })
+	s.textPages["comments"] = p
+
+	s.nextNewComment() // Not laid out: no anchors at all
+
+	p.view.SetRect(0, 0, 60, 5)
+	p.reflow(60)
+	page.Comments = append(page.Comments, Comment{ID: "3", Author: "c", Body: "new"}, Comment{ID: "4", Author: "d", Body: "new"})
+	p.anchors = p.anchors[:1] // Comments merged after the last render
+	s.nextNewComment()
+	if len(p.anchors) < len(page.blocks()) {
+		t.Errorf("Expected the page to be laid out again, got anchors %v", p.anchors)
+	}
+}
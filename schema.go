@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// schemaVersion is the version of the JSON documents gn-text writes (docs/json-schema.md).
+// It changes when a key is removed or renamed or its type changes; keys may be added
+// within a version.
+const schemaVersion = 1
+
+// ArticleListDocument is the JSON document of an article list
+type ArticleListDocument struct {
+	SchemaVersion int           `json:"schema_version"`
+	GeneratedAt   string        `json:"generated_at"`
+	Section       string        `json:"section"`
+	Articles      []ArticleJSON `json:"articles"`
+}
+
+// TopicDocument is the JSON document of a topic with its comments
+type TopicDocument struct {
+	SchemaVersion int       `json:"schema_version"`
+	GeneratedAt   string    `json:"generated_at"`
+	Topic         TopicJSON `json:"topic"`
+}
+
+// WatchAlertDocument is the JSON document `gn-text watch` writes to its command for a topic
+// matching a watch rule
+type WatchAlertDocument struct {
+	SchemaVersion int         `json:"schema_version"`
+	GeneratedAt   string      `json:"generated_at"`
+	Rule          string      `json:"rule"` // Name of the rule the topic matched
+	Article       ArticleJSON `json:"article"`
+}
+
+// ArticleJSON is an article of a list. Values the list does not carry are null.
+type ArticleJSON struct {
+	ID           string  `json:"id"`
+	Title        string  `json:"title"`
+	URL          string  `json:"url"`  // GeekNews topic page
+	Link         *string `json:"link"` // External article; null for posts without one
+	Domain       string  `json:"domain"`
+	Author       *string `json:"author"`
+	Points       *int    `json:"points"`
+	CommentCount *int    `json:"comment_count"`
+	PostedAt     *string `json:"posted_at"`
+	Summary      *string `json:"summary"` // Topic text; from the feed only
+}
+
+// TopicJSON is a topic page: the topic, its summary and its comment tree
+type TopicJSON struct {
+	ID           string        `json:"id"`
+	Title        string        `json:"title"`
+	URL          string        `json:"url"`
+	Link         *string       `json:"link"`
+	Domain       string        `json:"domain"`
+	Author       *string       `json:"author"`
+	Points       *int          `json:"points"`
+	PostedAt     *string       `json:"posted_at"`
+	PostedText   string        `json:"posted_text"` // As displayed, e.g. "21시간전"
+	Summary      string        `json:"summary"`
+	ArticleText  *string       `json:"article_text"` // Extracted external article; null unless asked for
+	CommentCount int           `json:"comment_count"`
+	Comments     []CommentJSON `json:"comments"` // Top-level comments, replies nested
+}
+
+// CommentJSON is a comment with its replies
+type CommentJSON struct {
+	ID         string        `json:"id"`
+	URL        string        `json:"url"`
+	Author     string        `json:"author"`
+	Body       string        `json:"body"` // Empty for deleted comments
+	PostedAt   *string       `json:"posted_at"`
+	PostedText string        `json:"posted_text"`
+	Replies    []CommentJSON `json:"replies"`
+}
+
+// newArticleList returns the JSON document of the articles of a section
+func newArticleList(section string, articles []Article, now time.Time) ArticleListDocument {
+	doc := ArticleListDocument{
+		SchemaVersion: schemaVersion,
+		GeneratedAt:   formatJSONTime(now),
+		Section:       section,
+		Articles:      make([]ArticleJSON, 0, len(articles)),
+	}
+	for _, article := range articles {
+		doc.Articles = append(doc.Articles, articleJSON(article))
+	}
+	return doc
+}
+
+func articleJSON(article Article) ArticleJSON {
+	a := ArticleJSON{
+		ID:           extractTopicID(article.CommentsLink),
+		Title:        article.Title,
+		URL:          article.CommentsLink,
+		Link:         optionalString(listField(article, "link")),
+		Domain:       article.Domain,
+		Author:       optionalString(article.Author),
+		Points:       optionalInt(article.Points),
+		CommentCount: optionalInt(article.Comments),
+		Summary:      optionalString(article.Summary),
+	}
+	if !article.Posted.IsZero() {
+		a.PostedAt = optionalString(formatJSONTime(article.Posted))
+	}
+	return a
+}
+
+// newTopicDocument returns the JSON document of a topic page. Comments only show how long
+// ago they were posted, so their times are computed from now.
+func newTopicDocument(page *TopicPage, now time.Time) TopicDocument {
+	return TopicDocument{
+		SchemaVersion: schemaVersion,
+		GeneratedAt:   formatJSONTime(now),
+		Topic:         topicJSON(page, now),
+	}
+}
+
+func topicJSON(page *TopicPage, now time.Time) TopicJSON {
+	content := page.Content
+	t := TopicJSON{
+		ID:           page.ID,
+		Title:        content.Title,
+		URL:          geekNewsBaseURL + "topic?id=" + page.ID,
+		Domain:       "news.hada.io",
+		Author:       optionalString(content.Author),
+		Points:       optionalInt(content.Points),
+		PostedText:   content.Time,
+		Summary:      strings.TrimSpace(content.Body),
+		CommentCount: len(page.Comments),
+		Comments:     commentTree(page.Comments, now),
+	}
+	if strings.HasPrefix(content.ExternalLink, "http") {
+		t.Link = optionalString(content.ExternalLink)
+		t.Domain = extractDomainFromURL(content.ExternalLink)
+	}
+	if !content.Posted.IsZero() {
+		t.PostedAt = optionalString(formatJSONTime(content.Posted))
+	} else if posted, ok := relativeTime(content.Time, now); ok {
+		t.PostedAt = optionalString(formatJSONTime(posted))
+	}
+	return t
+}
+
+// commentTree nests comments, listed in page order with their depth, under their parents
+func commentTree(comments []Comment, now time.Time) []CommentJSON {
+	tree := make([]CommentJSON, 0)
+	var path []*[]CommentJSON // Reply list of each depth on the way to the last comment
+	for _, comment := range comments {
+		c := CommentJSON{
+			ID:         comment.ID,
+			URL:        geekNewsBaseURL + "comment?id=" + comment.ID,
+			Author:     comment.Author,
+			Body:       strings.TrimSpace(comment.Body),
+			PostedText: strings.TrimSpace(comment.Time),
+			Replies:    make([]CommentJSON, 0),
+		}
+		if posted, ok := relativeTime(comment.Time, now); ok {
+			c.PostedAt = optionalString(formatJSONTime(posted))
+		}
+
+		depth := min(comment.Depth, len(path)) // A reply skipping levels goes under the last comment
+		path = path[:depth]
+		siblings := &tree
+		if depth > 0 {
+			parent := path[depth-1]
+			siblings = &(*parent)[len(*parent)-1].Replies
+		}
+		*siblings = append(*siblings, c)
+		path = append(path, siblings)
+	}
+	return tree
+}
+
+// relativeTimeRegex matches the relative times GeekNews shows, e.g. "21시간전"
+var relativeTimeRegex = regexp.MustCompile(`^(\d+)\s*(초|분|시간|일|달|개월|년)\s*전$`)
+
+// relativeTime returns the time a relative time such as "2일전" stands for, counted back from now
+func relativeTime(text string, now time.Time) (time.Time, bool) {
+	text = strings.TrimSpace(text)
+	if text == "방금" || text == "방금전" || text == "방금 전" {
+		return now, true
+	}
+	m := relativeTimeRegex.FindStringSubmatch(text)
+	if m == nil {
+		return time.Time{}, false
+	}
+	n, _ := strconv.Atoi(m[1])
+	switch m[2] {
+	case "초":
+		return now.Add(-time.Duration(n) * time.Second), true
+	case "분":
+		return now.Add(-time.Duration(n) * time.Minute), true
+	case "시간":
+		return now.Add(-time.Duration(n) * time.Hour), true
+	case "일":
+		return now.AddDate(0, 0, -n), true
+	case "달", "개월":
+		return now.AddDate(0, -n, 0), true
+	}
+	return now.AddDate(-n, 0, 0), true
+}
+
+// formatJSONTime formats a time of a JSON document: RFC 3339, to the second, in KST
+func formatJSONTime(t time.Time) string {
+	return t.In(geekNewsTimeZone).Truncate(time.Second).Format(time.RFC3339)
+}
+
+func optionalString(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalInt(s string) *int {
+	n, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+// writeJSON writes v as indented JSON, leaving <, > and & unescaped
+func writeJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite the golden files in testdata/golden")
+
+// goldenTime is the generation time of the golden documents
+var goldenTime = time.Date(2026, 2, 4, 12, 0, 0, 0, geekNewsTimeZone)
+
+// checkGolden compares the JSON encoding of v with testdata/golden/name, or rewrites the
+// file when the tests run with -update
+func checkGolden(t *testing.T, name string, v any) {
+	t.Helper()
+	var out bytes.Buffer
+	if err := writeJSON(&out, v); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	path := filepath.Join("testdata", "golden", name)
+	if *updateGolden {
+		if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file (run go test -update to create it): %v", err)
+	}
+	if !bytes.Equal(out.Bytes(), want) {
+		t.Errorf("%s changed shape; if intended, bump schemaVersion when keys are removed or retyped and run go test -update. Got:\n%s", path, out.String())
+	}
+}
+
+func TestArticleListGolden(t *testing.T) {
+	articles, err := fetchSection("top", testdataFetch(t, true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkGolden(t, "articles_top.json", newArticleList("top", articles, goldenTime))
+
+	homepage, err := fetchSection("new", testdataFetch(t, true))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	checkGolden(t, "articles_new.json", newArticleList("new", homepage, goldenTime))
+}
+
+func TestTopicGolden(t *testing.T) {
+	for name, fixture := range map[string]string{
+		"topic_full.json":     "testdata/geeknews_topic_full.html",
+		"topic_comments.json": "testdata/geeknews_topic_comments.html",
+	} {
+		html, err := os.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("Failed to read test fixture: %v", err)
+		}
+		page, err := parseTopicPage("26364", string(html))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		checkGolden(t, name, newTopicDocument(page, goldenTime))
+	}
+}
+
+func TestCommentTree(t *testing.T) {
+	comments := []Comment{
+		{ID: "1", Depth: 0}, {ID: "2", Depth: 1}, {ID: "3", Depth: 2}, {ID: "4", Depth: 1},
+		{ID: "5", Depth: 0}, {ID: "6", Depth: 3}, // Skips levels: goes under 5
+	}
+	tree := commentTree(comments, goldenTime)
+
+	if len(tree) != 2 || tree[0].ID != "1" || tree[1].ID != "5" {
+		t.Fatalf("Expected comments 1 and 5 at the top, got %+v", tree)
+	}
+	replies := tree[0].Replies
+	if len(replies) != 2 || replies[0].ID != "2" || replies[1].ID != "4" || replies[0].Replies[0].ID != "3" {
+		t.Errorf("Unexpected replies of comment 1: %+v", replies)
+	}
+	if len(tree[1].Replies) != 1 || tree[1].Replies[0].ID != "6" {
+		t.Errorf("Expected comment 6 under comment 5, got %+v", tree[1].Replies)
+	}
+	if tree[1].Replies[0].Replies == nil {
+		t.Error("Expected replies to be an empty array, not null")
+	}
+}
+
+func TestRelativeTime(t *testing.T) {
+	tests := map[string]string{
+		"방금":    "2026-02-04T12:00:00+09:00",
+		"30초전":  "2026-02-04T11:59:30+09:00",
+		"5분전":   "2026-02-04T11:55:00+09:00",
+		"21시간전": "2026-02-03T15:00:00+09:00",
+		"2일 전":  "2026-02-02T12:00:00+09:00",
+		"3개월전":  "2025-11-04T12:00:00+09:00",
+		"1년전":   "2025-02-04T12:00:00+09:00",
+	}
+	for text, want := range tests {
+		got, ok := relativeTime(text, goldenTime)
+		if !ok || formatJSONTime(got) != want {
+			t.Errorf("relativeTime(%q) = %s, %v; want %s", text, formatJSONTime(got), ok, want)
+		}
+	}
+	if _, ok := relativeTime("2026-02-03", goldenTime); ok {
+		t.Error("Expected absolute dates not to parse as relative times")
+	}
+}
@@ -0,0 +1,275 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// styleTagRegex matches tview color and region tags, which searching must not look into
+var styleTagRegex = regexp.MustCompile(`\[([a-zA-Z\-]*|#[0-9a-zA-Z]*)(:([a-zA-Z\-]*|#[0-9a-zA-Z]*)?(:([bdilrsu]+|\-)?)?)?\]|\["[^"]*"\]`)
+
+// tagRegex matches an escaped tview tag ("[x[]", group 1 without the last "[") or a style tag
+var tagRegex = regexp.MustCompile(`(\[[a-zA-Z0-9_,;: \-\."#]+\[*)\[\]|` + styleTagRegex.String())
+
+// renderFunc lays out the text of a page for width columns. It returns the lines and the
+// first line of each block (topic, comment, paragraph) that the scroll position is anchored
+// to when the page is laid out again.
+type renderFunc func(width int) (lines []string, anchors []int)
+
+// textPage is a scrollable text page (comments or article) that can be searched with '/'.
+// Its text is laid out again whenever the view's width changes.
+type textPage struct {
+	layout *tview.Flex
+	view   *tview.TextView
+	input  *tview.InputField
+
+	render  renderFunc
+	width   int    // Width the text was laid out for
+	text    string // Text without search highlights
+	anchors []int
+
+	topicID string          // Topic shown, for saving where it was read up to
+	resume  *scrollPosition // Where to scroll once the text is first laid out
+
+	query   string
+	matches int
+	current int
+}
+
+func newTextPage(render renderFunc) *textPage {
+	p := &textPage{render: render}
+	p.view = tview.NewTextView().
+		SetDynamicColors(true).
+		SetRegions(true).
+		SetScrollable(true)
+	p.layout = tview.NewFlex().SetDirection(tview.FlexRow).AddItem(&reflowView{p.view, p}, 0, 1, true)
+	return p
+}
+
+// staticText renders text as is, for messages that need no wrapping
+func staticText(text string) renderFunc {
+	return func(int) ([]string, []int) {
+		return []string{text}, nil
+	}
+}
+
+// reflowView draws the text view of a textPage, laying the text out for the current width first
+type reflowView struct {
+	*tview.TextView
+	page *textPage
+}
+
+func (v *reflowView) Draw(screen tcell.Screen) {
+	_, _, width, _ := v.GetInnerRect()
+	v.page.reflow(width)
+	v.TextView.Draw(screen)
+}
+
+// reflow lays the text out for width (capped at wrap_width) if it changed, keeping the block
+// at the top of the view in place
+func (p *textPage) reflow(width int) {
+	if settings.WrapWidth > 0 {
+		width = min(width, settings.WrapWidth)
+	}
+	if width <= 0 || width == p.width {
+		return
+	}
+
+	row, _ := p.view.GetScrollOffset()
+	block, offset := anchorAt(p.anchors, row)
+	if p.resume != nil {
+		block, offset = p.resume.Block, p.resume.Offset
+		p.resume = nil
+	}
+
+	p.width = width
+	lines, anchors := p.render(width)
+	p.text = strings.Join(lines, "\n")
+	p.anchors = anchors
+	p.highlight()
+
+	if block >= 0 && block < len(anchors) {
+		end := len(lines)
+		if block+1 < len(anchors) {
+			end = anchors[block+1]
+		}
+		p.view.ScrollTo(min(anchors[block]+offset, max(end-1, anchors[block])), 0)
+	}
+}
+
+// relayout lays the text out again for the same width, keeping the scroll position
+func (p *textPage) relayout() {
+	row, _ := p.view.GetScrollOffset()
+	width := p.width
+	p.width = 0
+	p.anchors = nil
+	p.reflow(width)
+	p.view.ScrollTo(row, 0)
+}
+
+// anchorAt returns the block containing line row and how many lines into the block it is,
+// or -1 if row comes before the first block
+func anchorAt(anchors []int, row int) (block, offset int) {
+	block = -1
+	for i, start := range anchors {
+		if start > row {
+			break
+		}
+		block = i
+	}
+	if block < 0 {
+		return -1, 0
+	}
+	return block, row - anchors[block]
+}
+
+// startSearch shows the search input below the current text page and focuses it
+func (s *session) startSearch() {
+	p := s.textPages[s.frontPage()]
+	if p == nil {
+		return
+	}
+
+	if p.input == nil {
+		previous := p.query
+		p.input = tview.NewInputField().SetLabel("/").SetText(p.query)
+		p.input.SetChangedFunc(func(query string) {
+			p.search(query)
+			s.showSearchStatus(p)
+		})
+		p.input.SetDoneFunc(func(key tcell.Key) {
+			if key == tcell.KeyEscape {
+				// Cancel: go back to the previous search, if any
+				p.search(previous)
+			}
+			p.layout.RemoveItem(p.input)
+			p.input = nil
+			s.app.SetFocus(p.view)
+			s.showSearchStatus(p)
+		})
+		p.layout.AddItem(p.input, 1, 0, false)
+	}
+	s.app.SetFocus(p.input)
+}
+
+// nextMatch moves to the next (step 1) or previous (step -1) match of the current search
+func (s *session) nextMatch(step int) {
+	p := s.textPages[s.frontPage()]
+	if p == nil || p.matches == 0 {
+		return
+	}
+	p.current = (p.current + step + p.matches) % p.matches
+	p.showCurrent()
+	s.showSearchStatus(p)
+}
+
+// showSearchStatus shows the query and match counter in the status bar
+func (s *session) showSearchStatus(p *textPage) {
+	switch {
+	case p.query == "":
+		s.setStatus("")
+	case p.matches == 0:
+		s.setStatus(fmt.Sprintf("/%s  일치 없음", p.query))
+	default:
+		s.setStatus(fmt.Sprintf("/%s  %d/%d", p.query, p.current+1, p.matches))
+	}
+}
+
+// search highlights every match of query and jumps to the first one
+func (p *textPage) search(query string) {
+	p.query = query
+	p.current = 0
+
+	p.highlight()
+	if p.matches > 0 {
+		p.showCurrent()
+	}
+}
+
+// highlight shows the text with the matches of the current query highlighted
+func (p *textPage) highlight() {
+	highlighted, matches := highlightMatches(p.text, p.query)
+	p.matches = matches
+	if p.current >= matches {
+		p.current = 0
+	}
+	p.view.SetText(highlighted)
+	if matches > 0 {
+		p.view.Highlight(searchRegionID(p.current))
+	} else {
+		p.view.Highlight()
+	}
+}
+
+func (p *textPage) showCurrent() {
+	p.view.Highlight(searchRegionID(p.current)).ScrollToHighlight()
+}
+
+func searchRegionID(index int) string {
+	return fmt.Sprintf("search-%d", index)
+}
+
+// highlightMatches wraps every match of query in text with a region and the theme's match
+// style, leaving style tags untouched. Matching is smart-case: case-insensitive unless
+// query contains an upper-case letter.
+func highlightMatches(text, query string) (string, int) {
+	q := []rune(query)
+	if len(q) == 0 {
+		return text, 0
+	}
+	foldCase := !hasUpper(query)
+
+	var b strings.Builder
+	matches := 0
+	last := 0
+	style := "" // Style tag in effect, restored after each match
+	for _, loc := range append(tagRegex.FindAllStringSubmatchIndex(text, -1), []int{len(text), len(text), -1, -1}) {
+		// Search the plain text between style tags
+		segment := []rune(text[last:loc[0]])
+		for i := 0; i < len(segment); {
+			if i+len(q) <= len(segment) && runesEqual(segment[i:i+len(q)], q, foldCase) {
+				fmt.Fprintf(&b, `["%s"]%s%s[""]`, searchRegionID(matches), paint(theme.Match, string(segment[i:i+len(q)])), style)
+				matches++
+				i += len(q)
+				continue
+			}
+			b.WriteRune(segment[i])
+			i++
+		}
+		// Escaped brackets ("[x[]") are text, neither searched nor a style
+		tag := text[loc[0]:loc[1]]
+		if loc[2] < 0 && !strings.HasPrefix(tag, `["`) {
+			style = tag
+		}
+		b.WriteString(tag)
+		last = loc[1]
+	}
+
+	return b.String(), matches
+}
+
+func runesEqual(a, b []rune, foldCase bool) bool {
+	for i := range a {
+		if a[i] == b[i] {
+			continue
+		}
+		if !foldCase || unicode.ToLower(a[i]) != unicode.ToLower(b[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func hasUpper(s string) bool {
+	for _, r := range s {
+		if unicode.IsUpper(r) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestHighlightMatches(t *testing.T) {
+	tests := []struct {
+		text    string
+		query   string
+		matches int
+	}{
+		{"Go is fun. go go", "go", 3},
+		{"Go is fun. go go", "Go", 1},
+		{"라이브러리 추천: 라이브러리 목록", "라이브러리", 2},
+		{"[yellow]yellow title[-]", "yellow", 1},
+		{"[yellow]yellow title[-]", "-", 0},
+		{"nothing here", "missing", 0},
+		{"empty query", "", 0},
+	}
+
+	for _, test := range tests {
+		_, matches := highlightMatches(test.text, test.query)
+		if matches != test.matches {
+			t.Errorf("highlightMatches(%q, %q) found %d matches, expected %d", test.text, test.query, matches, test.matches)
+		}
+	}
+}
+
+func TestHighlightMatchesKeepsStyleTags(t *testing.T) {
+	highlighted, matches := highlightMatches("[gray]user1 · 1시간전[-]\n본문 user1", "user1")
+
+	if matches != 2 {
+		t.Fatalf("Expected 2 matches, got %d", matches)
+	}
+	if !strings.HasPrefix(highlighted, "[gray]") || !strings.Contains(highlighted, "[-]\n") {
+		t.Errorf("Expected style tags to be preserved, got %q", highlighted)
+	}
+	if !strings.Contains(highlighted, `["search-0"]`) || !strings.Contains(highlighted, `["search-1"]`) {
+		t.Errorf("Expected a region per match, got %q", highlighted)
+	}
+}
+
+func TestHighlightMatchesNextToEscapedBrackets(t *testing.T) {
+	// A comment quoting "[tag]" is escaped to "[tag[]", which is text rather than a style
+	text := "[gray]" + tview.Escape("[tag]") + "go[-]"
+	highlighted, matches := highlightMatches(text, "go")
+
+	if matches != 1 {
+		t.Fatalf("Expected 1 match, got %d", matches)
+	}
+	want := `[gray][tag[]["search-0"]` + paint(theme.Match, "go") + `[gray][""][-]`
+	if highlighted != want {
+		t.Errorf("Expected the gray style restored after the match, got %q, want %q", highlighted, want)
+	}
+}
+
+func TestTextPageReflowKeepsAnchor(t *testing.T) {
+	var comments []Comment
+	for i := 0; i < 20; i++ {
+		comments = append(comments, Comment{Author: fmt.Sprintf("user%d", i), Body: strings.Repeat("긴 댓글 내용입니다. ", 8)})
+	}
+	page := &TopicPage{Comments: comments}
+	p := newTextPage(func(width int) ([]string, []int) { return layoutTopicPage(page, width) })
+
+	p.reflow(100)
+	p.view.ScrollTo(p.anchors[12]+1, 0)
+
+	p.reflow(40)
+	row, _ := p.view.GetScrollOffset()
+	if row != p.anchors[12]+1 {
+		t.Errorf("Expected to stay one line into comment 12 (line %d), got line %d", p.anchors[12]+1, row)
+	}
+	if !strings.Contains(p.text, "user19") || p.width != 40 {
+		t.Errorf("Expected text laid out again for width 40, got width %d", p.width)
+	}
+}
+
+func TestTextPageReflowKeepsSearch(t *testing.T) {
+	p := newTextPage(func(width int) ([]string, []int) {
+		return layoutText("첫 문단 검색어\n\n둘째 문단 검색어", width)
+	})
+	p.reflow(80)
+	p.search("검색어")
+	p.current = 1
+
+	p.reflow(20)
+	if p.matches != 2 || p.current != 1 {
+		t.Errorf("Expected search to survive reflow, got %d matches, current %d", p.matches, p.current)
+	}
+}
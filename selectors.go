@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/rivo/tview"
+)
+
+// Selector is a named CSS selector used to scrape GeekNews pages
+type Selector struct {
+	Name     string
+	Query    string
+	Required bool // A miss on a required selector means the page layout likely changed
+}
+
+// Selectors for GeekNews pages (see docs/geeknews-design-plan.md "Concrete Selectors")
+var (
+	selCommentThread = Selector{Name: "comment.thread", Query: "#comment_thread"}
+	selCommentRow    = Selector{Name: "comment.row", Query: "#comment_thread .comment_row"}
+	selCommentAuthor = Selector{Name: "comment.author", Query: ".commentinfo a[href^='/user?id=']"}
+	selCommentTime   = Selector{Name: "comment.time", Query: ".commentinfo a[href^='comment?id=']"}
+	selCommentBody   = Selector{Name: "comment.body", Query: ".commentTD .comment_contents", Required: true}
+
+	selCommentPageTopic = Selector{Name: "comment.page.topic", Query: ".commentinfo a[href^='topic?id=']", Required: true}
+
+	selTopicTitle        = Selector{Name: "topic.title", Query: ".topictitle h1", Required: true}
+	selTopicLink         = Selector{Name: "topic.link", Query: ".topictitle.link > a"}
+	selTopicLinkFallback = Selector{Name: "topic.link.fallback", Query: ".topictitle > a"}
+	selTopicLinkTitle    = Selector{Name: "topic.link.title", Query: "h1"}
+	selTopicBody         = Selector{Name: "topic.body", Query: "#topic_contents"}
+	selTopicAuthor       = Selector{Name: "topic.author", Query: ".topicinfo a[href^='/user?id=']", Required: true}
+	selTopicTime         = Selector{Name: "topic.time", Query: ".topicinfo span[title]"}
+	selTopicPoints       = Selector{Name: "topic.points", Query: ".topicinfo span[id^='tp']"}
+
+	selTopicRow         = Selector{Name: "list.row", Query: "div.topic_row"}
+	selTopicRowTopic    = Selector{Name: "list.topic", Query: ".topicdesc > a[href^='topic?id='], .topicinfo a[href^='topic?id=']", Required: true}
+	selTopicRowComments = Selector{Name: "list.comments", Query: ".topicinfo a[href*='go=comments']"}
+
+	selUserRow          = Selector{Name: "user.row", Query: ".userinfo table tr", Required: true}
+	selUserCommentRow   = Selector{Name: "user.comment.row", Query: ".comment_row"}
+	selUserCommentTopic = Selector{Name: "user.comment.topic", Query: ".commentinfo a[href^='topic?id=']"}
+)
+
+// selectorRegistry lists every selector used by the parsers, in page order
+var selectorRegistry = []Selector{
+	selCommentThread,
+	selCommentRow,
+	selCommentAuthor,
+	selCommentTime,
+	selCommentBody,
+	selCommentPageTopic,
+	selTopicTitle,
+	selTopicLink,
+	selTopicLinkFallback,
+	selTopicLinkTitle,
+	selTopicBody,
+	selTopicAuthor,
+	selTopicTime,
+	selTopicPoints,
+	selTopicRow,
+	selTopicRowTopic,
+	selTopicRowComments,
+	selUserRow,
+	selUserCommentRow,
+	selUserCommentTopic,
+}
+
+// ParseReport records how many elements each selector matched during a parse
+type ParseReport struct {
+	Hits map[string]int // Keyed by selector name; only selectors that were evaluated are present
+}
+
+func newParseReport() *ParseReport {
+	return &ParseReport{Hits: make(map[string]int)}
+}
+
+// find runs sel against s and records the number of matches
+func (r *ParseReport) find(s *goquery.Selection, sel Selector) *goquery.Selection {
+	found := s.Find(sel.Query)
+	r.Hits[sel.Name] += found.Length()
+	return found
+}
+
+// Missing returns the evaluated selectors that matched nothing, in registry order
+func (r *ParseReport) Missing() []Selector {
+	var missing []Selector
+	for _, sel := range selectorRegistry {
+		if hits, evaluated := r.Hits[sel.Name]; evaluated && hits == 0 {
+			missing = append(missing, sel)
+		}
+	}
+	return missing
+}
+
+// MissingRequired returns the required selectors that matched nothing
+func (r *ParseReport) MissingRequired() []Selector {
+	var missing []Selector
+	for _, sel := range r.Missing() {
+		if sel.Required {
+			missing = append(missing, sel)
+		}
+	}
+	return missing
+}
+
+// Merge adds the hit counts of other into r
+func (r *ParseReport) Merge(other *ParseReport) {
+	if other == nil {
+		return
+	}
+	for name, hits := range other.Hits {
+		r.Hits[name] += hits
+	}
+}
+
+// layoutWarning returns a warning line when a required selector missed, or "" otherwise
+func layoutWarning(report *ParseReport) string {
+	if report == nil {
+		return ""
+	}
+	missing := report.MissingRequired()
+	if len(missing) == 0 {
+		return ""
+	}
+	var names []string
+	for _, sel := range missing {
+		names = append(names, fmt.Sprintf("%s (%s)", sel.Name, sel.Query))
+	}
+	return paint(theme.Error, "GeekNews layout may have changed: "+tview.Escape(strings.Join(names, ", ")))
+}
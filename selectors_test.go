@@ -0,0 +1,127 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestParseReportFullTopicHasNoMissingRequired(t *testing.T) {
+	htmlContent, err := os.ReadFile("testdata/geeknews_topic_full.html")
+	if err != nil {
+		t.Fatalf("Failed to read test fixture: %v", err)
+	}
+
+	_, topicReport, err := parseGeekNewsTopicContent(string(htmlContent))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	_, commentsReport, err := parseGeekNewsComments(string(htmlContent))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	report := newParseReport()
+	report.Merge(topicReport)
+	report.Merge(commentsReport)
+
+	if missing := report.MissingRequired(); len(missing) != 0 {
+		t.Errorf("Expected no missing required selectors, got %v", missing)
+	}
+	if layoutWarning(report) != "" {
+		t.Errorf("Expected no layout warning, got %q", layoutWarning(report))
+	}
+}
+
+func TestParseReportCommentHits(t *testing.T) {
+	htmlContent, err := os.ReadFile("testdata/geeknews_topic_comments.html")
+	if err != nil {
+		t.Fatalf("Failed to read test fixture: %v", err)
+	}
+
+	comments, report, err := parseGeekNewsComments(string(htmlContent))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if report.Hits[selCommentRow.Name] != len(comments) {
+		t.Errorf("Expected %d row hits, got %d", len(comments), report.Hits[selCommentRow.Name])
+	}
+	if report.Hits[selCommentBody.Name] == 0 {
+		t.Error("Expected comment body selector to match")
+	}
+}
+
+func TestParseReportChangedLayout(t *testing.T) {
+	changedHTML := `<div class="headline"><a href="https://example.com"><h2>Renamed</h2></a></div>`
+
+	content, report, err := parseGeekNewsTopicContent(changedHTML)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if content.Title != "" {
+		t.Errorf("Expected empty title, got %q", content.Title)
+	}
+
+	missing := report.MissingRequired()
+	found := false
+	for _, sel := range missing {
+		if sel.Name == selTopicTitle.Name {
+			found = true
+		}
+		if !sel.Required {
+			t.Errorf("Expected only required selectors, got %q", sel.Name)
+		}
+	}
+	if !found {
+		t.Errorf("Expected %q in missing required selectors, got %v", selTopicTitle.Name, missing)
+	}
+
+	warning := layoutWarning(report)
+	if !strings.Contains(warning, "GeekNews layout may have changed") {
+		t.Errorf("Expected layout warning, got %q", warning)
+	}
+}
+
+func TestParseReportOptionalMissIsNotWarned(t *testing.T) {
+	// A topic without comments has an empty thread, which is not a layout change
+	_, report, err := parseGeekNewsComments(`<div id='comment_thread' class='comment_thread'></div>`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(report.Missing()) != 1 || report.Missing()[0].Name != selCommentRow.Name {
+		t.Errorf("Expected only %q to be missing, got %v", selCommentRow.Name, report.Missing())
+	}
+	if warning := layoutWarning(report); warning != "" {
+		t.Errorf("Expected no warning, got %q", warning)
+	}
+}
+
+func TestParseReportAskTopicIsNotWarned(t *testing.T) {
+	// An Ask topic has no external link and may have no points or comments yet
+	askHTML := `<div class='topictitle'><h1>질문 있습니다</h1></div>
+<div class='topicinfo'><a href='/user?id=asker'>asker</a></div>`
+
+	report := newParseReport()
+	_, topicReport, _ := parseGeekNewsTopicContent(askHTML)
+	_, commentsReport, _ := parseGeekNewsComments(askHTML)
+	report.Merge(topicReport)
+	report.Merge(commentsReport)
+
+	if missing := report.MissingRequired(); len(missing) != 0 {
+		t.Errorf("Expected no missing required selectors, got %v", missing)
+	}
+}
+
+func TestParseReportUserRowNeedsUserInfo(t *testing.T) {
+	// Any page with a table used to satisfy the user row selector
+	_, report, err := parseGeekNewsUserProfile(`<table><tr><td>id:</td><td>someone</td></tr></table>`)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	missing := report.MissingRequired()
+	if len(missing) != 1 || missing[0].Name != selUserRow.Name {
+		t.Errorf("Expected %q to be missing, got %v", selUserRow.Name, missing)
+	}
+}
@@ -0,0 +1,423 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CommentsDocument is the JSON document of the comments of a topic
+type CommentsDocument struct {
+	SchemaVersion int           `json:"schema_version"`
+	GeneratedAt   string        `json:"generated_at"`
+	TopicID       string        `json:"topic_id"`
+	CommentCount  int           `json:"comment_count"`
+	Comments      []CommentJSON `json:"comments"`
+}
+
+// ArticleTextDocument is the JSON document of the extracted external article of a topic
+type ArticleTextDocument struct {
+	SchemaVersion int    `json:"schema_version"`
+	GeneratedAt   string `json:"generated_at"`
+	TopicID       string `json:"topic_id"`
+	Link          string `json:"link"`
+	Text          string `json:"text"`
+}
+
+// ErrorDocument is the JSON document of a failed API request
+type ErrorDocument struct {
+	SchemaVersion int    `json:"schema_version"`
+	Status        int    `json:"status"`
+	Error         string `json:"error"`
+}
+
+// HealthDocument is the JSON document of the health endpoint
+type HealthDocument struct {
+	SchemaVersion int    `json:"schema_version"`
+	Status        string `json:"status"`
+	Version       string `json:"version"`
+	StartedAt     string `json:"started_at"`
+}
+
+// apiError is an error answered with an HTTP status
+type apiError struct {
+	status int
+	err    error
+}
+
+func (e *apiError) Error() string { return e.err.Error() }
+
+func newAPIError(status int, format string, args ...any) *apiError {
+	return &apiError{status: status, err: fmt.Errorf(format, args...)}
+}
+
+// apiServer serves GeekNews data as JSON from the page cache. Documents are generated
+// at the time their pages were fetched, so they and their ETags stay the same as long
+// as the pages are cached.
+type apiServer struct {
+	fetch   func(url string) (string, time.Time, error)
+	started time.Time
+
+	feedTTL time.Duration // How long a built feed is served before it is built again
+	feedsMu sync.Mutex
+	feeds   map[string]*builtFeed // By section, feed size and whether articles are included
+}
+
+// builtFeed is a feed served until it is feedTTL old
+type builtFeed struct {
+	mu    sync.Mutex // Held while building, so concurrent requests wait for one build
+	feed  *FullFeed
+	built time.Time
+}
+
+func newAPIServer(fetch func(string) (string, time.Time, error)) *apiServer {
+	return &apiServer{
+		fetch:   fetch,
+		started: time.Now(),
+		feedTTL: time.Duration(settings.CacheTTLMemory) * time.Second,
+		feeds:   make(map[string]*builtFeed),
+	}
+}
+
+// upstreamFetches bounds how many pages the server fetches from the network at once
+const upstreamFetches = 4
+
+// fetchGroup fetches pages for concurrent requests: fetches of a URL already being fetched
+// wait for its result instead of fetching it again, and at most a fixed number run at once
+type fetchGroup struct {
+	fetch func(string) (string, error)
+	slots chan struct{}
+
+	mu    sync.Mutex
+	calls map[string]*fetchCall // In flight, by URL
+}
+
+type fetchCall struct {
+	done chan struct{} // Closed when data and err are set
+	data string
+	err  error
+}
+
+func newFetchGroup(fetch func(string) (string, error), limit int) *fetchGroup {
+	return &fetchGroup{fetch: fetch, slots: make(chan struct{}, limit), calls: make(map[string]*fetchCall)}
+}
+
+// get fetches url, or waits for the fetch of url in flight
+func (g *fetchGroup) get(url string) (string, error) {
+	g.mu.Lock()
+	if call, ok := g.calls[url]; ok {
+		g.mu.Unlock()
+		<-call.done
+		return call.data, call.err
+	}
+	call := &fetchCall{done: make(chan struct{})}
+	g.calls[url] = call
+	g.mu.Unlock()
+
+	g.slots <- struct{}{}
+	call.data, call.err = g.fetch(url)
+	<-g.slots
+
+	g.mu.Lock()
+	delete(g.calls, url)
+	g.mu.Unlock()
+	close(call.done)
+	return call.data, call.err
+}
+
+// handler routes the API endpoints
+func (s *apiServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/health", s.serve(s.health))
+	mux.HandleFunc("/api/articles", s.serve(s.articles))
+	mux.HandleFunc("/api/topics/", s.serve(s.topic))
+	mux.HandleFunc("/feed.atom", s.serve(s.feed))
+	mux.HandleFunc("/", s.serve(func(r *http.Request) (any, error) {
+		return nil, newAPIError(http.StatusNotFound, "no such endpoint: %s", r.URL.Path)
+	}))
+	return mux
+}
+
+// serve answers a GET request with the document returned by handle, as JSON or, for the
+// feed, Atom, tagged with an ETag; or with a JSON error document
+func (s *apiServer) serve(handle func(r *http.Request) (any, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		status := s.respond(w, r, handle)
+		log.Printf("%s %s %d %s", r.Method, r.URL.RequestURI(), status, time.Since(start).Round(time.Millisecond))
+	}
+}
+
+func (s *apiServer) respond(w http.ResponseWriter, r *http.Request, handle func(r *http.Request) (any, error)) int {
+	var doc any
+	var err error
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		err = newAPIError(http.StatusMethodNotAllowed, "method %s not allowed", r.Method)
+	} else {
+		doc, err = handle(r)
+	}
+
+	status := http.StatusOK
+	if err != nil {
+		var apiErr *apiError
+		if !errors.As(err, &apiErr) {
+			apiErr = &apiError{status: http.StatusBadGateway, err: err} // Fetching from GeekNews failed
+		}
+		status = apiErr.status
+		doc = ErrorDocument{SchemaVersion: schemaVersion, Status: status, Error: apiErr.Error()}
+	}
+
+	var body bytes.Buffer
+	contentType := "application/json; charset=utf-8"
+	if feed, ok := doc.(*FullFeed); ok {
+		contentType = "application/atom+xml; charset=utf-8"
+		err = writeFullFeed(&body, feed)
+	} else {
+		err = writeJSON(&body, doc)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return http.StatusInternalServerError
+	}
+	w.Header().Set("Content-Type", contentType)
+	if status == http.StatusOK {
+		sum := sha256.Sum256(body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", "no-cache") // Revalidate with the ETag
+		if matchesETag(r.Header.Get("If-None-Match"), etag) {
+			w.WriteHeader(http.StatusNotModified)
+			return http.StatusNotModified
+		}
+	}
+	w.Header().Set("Content-Length", strconv.Itoa(body.Len()))
+	w.WriteHeader(status)
+	if r.Method != http.MethodHead {
+		w.Write(body.Bytes())
+	}
+	return status
+}
+
+// matchesETag reports whether an If-None-Match header lists etag
+func matchesETag(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == etag || candidate == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// health answers /api/health without contacting GeekNews
+func (s *apiServer) health(*http.Request) (any, error) {
+	return HealthDocument{
+		SchemaVersion: schemaVersion,
+		Status:        "ok",
+		Version:       version,
+		StartedAt:     formatJSONTime(s.started),
+	}, nil
+}
+
+// articles answers /api/articles?section=top&limit=30
+func (s *apiServer) articles(r *http.Request) (any, error) {
+	section, limit, err := sectionQuery(r, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	// The list is as old as the oldest page it was read from
+	var fetched time.Time
+	articles, err := fetchSection(section, func(url string) (string, error) {
+		data, at, err := s.fetch(url)
+		if err == nil && (fetched.IsZero() || at.Before(fetched)) {
+			fetched = at
+		}
+		return data, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(articles) > limit {
+		articles = articles[:limit]
+	}
+	return newArticleList(section, articles, fetched), nil
+}
+
+// feedSizes are the sizes feeds are built in. A feed of another length is cut from the next
+// larger one, so the feeds kept stay few whatever limits are asked for.
+var feedSizes = []int{10, 20, 30, 50}
+
+// feed answers /feed.atom?section=top&limit=20 with the full-content Atom feed. Building
+// one fetches every topic and article, so it is built once per memory cache TTL.
+func (s *apiServer) feed(r *http.Request) (any, error) {
+	section, limit, err := sectionQuery(r, 20)
+	if err != nil {
+		return nil, err
+	}
+	maxLimit := feedSizes[len(feedSizes)-1]
+	if limit < 1 || limit > maxLimit {
+		return nil, newAPIError(http.StatusBadRequest, "limit must be between 1 and %d, got %d", maxLimit, limit)
+	}
+	size := maxLimit
+	for _, n := range feedSizes {
+		if n >= limit {
+			size = n
+			break
+		}
+	}
+	withArticle := r.URL.Query().Get("article") != "0"
+
+	key := fmt.Sprintf("%s/%d/%t", section, size, withArticle)
+	s.feedsMu.Lock()
+	built, ok := s.feeds[key]
+	if !ok {
+		built = &builtFeed{}
+		s.feeds[key] = built
+	}
+	s.feedsMu.Unlock()
+
+	built.mu.Lock()
+	defer built.mu.Unlock()
+	if built.feed == nil || time.Since(built.built) >= s.feedTTL {
+		feed, err := buildFullFeed(section, size, withArticle, s.fetch)
+		if err != nil {
+			return nil, err
+		}
+		built.feed, built.built = feed, time.Now()
+	}
+	return built.feed.first(limit), nil
+}
+
+// sectionQuery reads the section and limit parameters of a list request
+func sectionQuery(r *http.Request, defaultLimit int) (string, int, error) {
+	section := r.URL.Query().Get("section")
+	if section == "" {
+		section = "top"
+	}
+	if _, ok := listSections[section]; !ok {
+		return "", 0, newAPIError(http.StatusBadRequest, "unknown section %q (available: top, new, ask, show)", section)
+	}
+	limit := defaultLimit
+	if value := r.URL.Query().Get("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			return "", 0, newAPIError(http.StatusBadRequest, "limit must be a positive number, got %q", value)
+		}
+		limit = n
+	}
+	return section, limit, nil
+}
+
+// topic answers /api/topics/{id}, /api/topics/{id}/comments and /api/topics/{id}/article
+func (s *apiServer) topic(r *http.Request) (any, error) {
+	id, part, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/topics/"), "/")
+	if _, err := strconv.Atoi(id); err != nil {
+		return nil, newAPIError(http.StatusNotFound, "no such topic: %q", id)
+	}
+	if part != "" && part != "comments" && part != "article" {
+		return nil, newAPIError(http.StatusNotFound, "no such endpoint: %s", r.URL.Path)
+	}
+
+	html, fetched, err := s.fetch(geekNewsBaseURL + "topic?id=" + id)
+	if err != nil {
+		return nil, err
+	}
+	page, err := parseTopicPage(id, html)
+	if err != nil {
+		return nil, err
+	}
+	if page.Content.Title == "" && len(page.Comments) == 0 {
+		return nil, newAPIError(http.StatusNotFound, "no such topic: %s", id)
+	}
+
+	switch part {
+	case "comments":
+		return CommentsDocument{
+			SchemaVersion: schemaVersion,
+			GeneratedAt:   formatJSONTime(fetched),
+			TopicID:       id,
+			CommentCount:  len(page.Comments),
+			Comments:      commentTree(page.Comments, fetched),
+		}, nil
+	case "article":
+		return s.article(page)
+	}
+	return newTopicDocument(page, fetched), nil
+}
+
+// article extracts the external article of a topic
+func (s *apiServer) article(page *TopicPage) (any, error) {
+	link := page.Content.ExternalLink
+	if !strings.HasPrefix(link, "http") {
+		return nil, newAPIError(http.StatusNotFound, "topic %s has no external article", page.ID)
+	}
+	html, fetched, err := s.fetch(link)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", link, err)
+	}
+	text, err := parseArticleText(html)
+	if err != nil {
+		return nil, newAPIError(http.StatusUnprocessableEntity, "%s: %v", link, err)
+	}
+	return ArticleTextDocument{
+		SchemaVersion: schemaVersion,
+		GeneratedAt:   formatJSONTime(fetched),
+		TopicID:       page.ID,
+		Link:          link,
+		Text:          strings.TrimSpace(text),
+	}, nil
+}
+
+// runServe implements `gn-text serve`, which serves the JSON API until interrupted
+func runServe(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.SetOutput(w)
+	addr := fs.String("addr", "127.0.0.1:8080", "Address to listen on")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+
+	upstream := newFetchGroup(fetchWebpage, upstreamFetches)
+	fetch := func(url string) (string, time.Time, error) {
+		return fetchCachedWith(url, upstream.get)
+	}
+	// Building a feed from cold pages takes a while: writes get more time than reads
+	server := &http.Server{
+		Addr:              *addr,
+		Handler:           newAPIServer(fetch).handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       30 * time.Second,
+		WriteTimeout:      3 * time.Minute,
+		IdleTimeout:       2 * time.Minute,
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdown)
+	}()
+
+	log.Printf("gn-text %s serving on http://%s/api/ and http://%s/feed.atom", version, *addr, *addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	return 0
+}
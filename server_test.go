@@ -0,0 +1,255 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testdataFetchTime serves the test fixtures as fetched at goldenTime. The page of topic
+// 26363 cannot be fetched.
+func testdataFetchTime(t *testing.T) func(string) (string, time.Time, error) {
+	fetchList := testdataFetch(t, true)
+	return func(url string) (string, time.Time, error) {
+		var path string
+		switch url {
+		case geekNewsBaseURL + "topic?id=26364":
+			path = "testdata/geeknews_topic_full.html"
+		case "https://www.anthropic.com/research/AI-assistance-coding-skills":
+			return "<html><body><article><p>" + strings.Repeat("Extracted article text. ", 20) + "</p></article></body></html>", goldenTime, nil
+		case geekNewsBaseURL + "topic?id=404", geekNewsBaseURL + "topic?id=26363":
+			return "", time.Time{}, errors.New("HTTP 503")
+		default:
+			data, err := fetchList(url)
+			return data, goldenTime, err
+		}
+		data, err := os.ReadFile(path)
+		return string(data), goldenTime, err
+	}
+}
+
+// newTestAPIServer serves the API from the test fixtures
+func newTestAPIServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(newAPIServer(testdataFetchTime(t)).handler())
+	t.Cleanup(server.Close)
+	return server
+}
+
+// getJSON requests path and decodes the response into v, returning the response
+func getJSON(t *testing.T, server *httptest.Server, path string, v any) *http.Response {
+	t.Helper()
+	resp, err := http.Get(server.URL + path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	if v != nil {
+		if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+			t.Fatalf("%s: invalid JSON: %v", path, err)
+		}
+	}
+	return resp
+}
+
+func TestServeHealth(t *testing.T) {
+	server := newTestAPIServer(t)
+	var doc HealthDocument
+	resp := getJSON(t, server, "/api/health", &doc)
+	if resp.StatusCode != http.StatusOK || doc.Status != "ok" || doc.SchemaVersion != schemaVersion {
+		t.Errorf("Unexpected health: %d %+v", resp.StatusCode, doc)
+	}
+}
+
+func TestServeArticles(t *testing.T) {
+	server := newTestAPIServer(t)
+	var doc ArticleListDocument
+	resp := getJSON(t, server, "/api/articles?limit=1", &doc)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if doc.Section != "top" || len(doc.Articles) != 1 || doc.GeneratedAt != formatJSONTime(goldenTime) {
+		t.Errorf("Unexpected list: %s, %d articles at %s", doc.Section, len(doc.Articles), doc.GeneratedAt)
+	}
+
+	etag := resp.Header.Get("ETag")
+	if etag == "" {
+		t.Fatal("Expected an ETag")
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL+"/api/articles?limit=1", nil)
+	req.Header.Set("If-None-Match", etag)
+	again, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	again.Body.Close()
+	if again.StatusCode != http.StatusNotModified {
+		t.Errorf("Expected 304 for the same ETag, got %d", again.StatusCode)
+	}
+
+	if other := getJSON(t, server, "/api/articles", nil); other.Header.Get("ETag") == etag {
+		t.Error("Expected a different list to have a different ETag")
+	}
+}
+
+func TestServeTopic(t *testing.T) {
+	server := newTestAPIServer(t)
+
+	var topic TopicDocument
+	if resp := getJSON(t, server, "/api/topics/26364", &topic); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if topic.Topic.ID != "26364" || topic.Topic.Title == "" || topic.Topic.CommentCount == 0 {
+		t.Errorf("Unexpected topic: %+v", topic.Topic)
+	}
+
+	var comments CommentsDocument
+	getJSON(t, server, "/api/topics/26364/comments", &comments)
+	if comments.TopicID != "26364" || comments.CommentCount != topic.Topic.CommentCount || len(comments.Comments) != len(topic.Topic.Comments) {
+		t.Errorf("Expected the comments of the topic, got %d of %d", comments.CommentCount, topic.Topic.CommentCount)
+	}
+
+	var article ArticleTextDocument
+	if resp := getJSON(t, server, "/api/topics/26364/article", &article); resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected 200, got %d", resp.StatusCode)
+	}
+	if article.Link != *topic.Topic.Link || !strings.Contains(article.Text, "Extracted article text.") {
+		t.Errorf("Unexpected article: %+v", article)
+	}
+}
+
+func TestServeFeed(t *testing.T) {
+	var mu sync.Mutex
+	fetches := 0
+	fetch := testdataFetchTime(t)
+	server := httptest.NewServer(newAPIServer(func(url string) (string, time.Time, error) {
+		mu.Lock()
+		fetches++
+		mu.Unlock()
+		return fetch(url)
+	}).handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/feed.atom")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK || !strings.HasPrefix(resp.Header.Get("Content-Type"), "application/atom+xml") || resp.Header.Get("ETag") == "" {
+		t.Errorf("Expected an Atom feed with an ETag, got %d %s", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	if !strings.Contains(string(body), "Extracted article text.") {
+		t.Errorf("Expected the article text in the feed, got:\n%s", body)
+	}
+
+	built := fetches
+	again := getJSON(t, server, "/feed.atom", nil)
+	if again.StatusCode != http.StatusOK || fetches != built {
+		t.Errorf("Expected the feed to be served again without fetching, got %d after %d fetches", fetches-built, built)
+	}
+
+	// Limits up to the same feed size share one build, cut to the limit
+	countEntries := func(path string) int {
+		resp, err := http.Get(server.URL + path)
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return strings.Count(string(body), "<entry>")
+	}
+	if entries := countEntries("/feed.atom?limit=1"); entries != 1 {
+		t.Errorf("Expected 1 entry, got %d", entries)
+	}
+	built = fetches
+	if entries := countEntries("/feed.atom?limit=7"); entries != 2 || fetches != built {
+		t.Errorf("Expected both entries without fetching, got %d entries after %d fetches", entries, fetches-built)
+	}
+}
+
+func TestServeErrors(t *testing.T) {
+	server := newTestAPIServer(t)
+	tests := map[string]int{
+		"/api/articles?section=best": http.StatusBadRequest,
+		"/api/articles?limit=x":      http.StatusBadRequest,
+		"/api/topics/abc":            http.StatusNotFound,
+		"/api/topics/26364/replies":  http.StatusNotFound,
+		"/api/nothing":               http.StatusNotFound,
+		"/api/topics/404":            http.StatusBadGateway,
+		"/api/topics/404/comments":   http.StatusBadGateway,
+		"/feed.atom?limit=0":         http.StatusBadRequest,
+		"/feed.atom?limit=-5":        http.StatusBadRequest,
+		"/feed.atom?limit=100000":    http.StatusBadRequest,
+	}
+	for path, status := range tests {
+		var doc ErrorDocument
+		resp := getJSON(t, server, path, &doc)
+		if resp.StatusCode != status || doc.Status != status || doc.Error == "" {
+			t.Errorf("%s: expected %d with an error document, got %d %+v", path, status, resp.StatusCode, doc)
+		}
+	}
+
+	resp, err := http.Post(server.URL+"/api/articles", "application/json", nil)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusMethodNotAllowed || resp.Header.Get("Allow") == "" {
+		t.Errorf("Expected 405 with Allow, got %d", resp.StatusCode)
+	}
+}
+
+func TestFetchGroup(t *testing.T) {
+	var mu sync.Mutex
+	calls := make(map[string]int)
+	running, most := 0, 0
+	release := make(chan struct{})
+	group := newFetchGroup(func(url string) (string, error) {
+		mu.Lock()
+		calls[url]++
+		running++
+		most = max(most, running)
+		mu.Unlock()
+		<-release
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return "page " + url, nil
+	}, 2)
+
+	var wg sync.WaitGroup
+	results := make(chan string, 20)
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			data, _ := group.get(url)
+			results <- data
+		}(strconv.Itoa(i % 4))
+	}
+	time.Sleep(50 * time.Millisecond) // Let the requests pile up
+	close(release)
+	wg.Wait()
+	close(results)
+
+	for data := range results {
+		if !strings.HasPrefix(data, "page ") {
+			t.Errorf("Unexpected result %q", data)
+		}
+	}
+	if most > 2 {
+		t.Errorf("Expected at most 2 fetches at once, got %d", most)
+	}
+	// Requests for a URL in flight, or waiting for a free slot, share its fetch
+	if len(calls) != 4 || calls["0"] != 1 || calls["1"] != 1 || calls["2"] != 1 || calls["3"] != 1 {
+		t.Errorf("Expected each of the 4 URLs to be fetched once, got %v", calls)
+	}
+}
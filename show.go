@@ -0,0 +1,229 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+	"golang.org/x/term"
+)
+
+// runShow prints a topic with its comments, and optionally its article, without starting the UI
+func runShow(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("show", flag.ContinueOnError)
+	fs.SetOutput(w)
+	width := fs.Int("width", 0, "Wrap text at this width (default wrap_width or the terminal width)")
+	noComments := fs.Bool("no-comments", false, "Print the topic without its comments")
+	withArticle := fs.Bool("article", false, "Also extract and print the external article")
+	asJSON := fs.Bool("json", false, "Print the topic as JSON (see docs/json-schema.md)")
+	fs.Usage = func() {
+		fmt.Fprintln(w, "Usage: gn-text show [flags] <topic ID or URL>")
+		fs.PrintDefaults()
+	}
+	topics, ok := parseInterspersed(fs, args)
+	if !ok {
+		return 2
+	}
+	if len(topics) != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	topicID, err := resolveTopicID(topics[0], fetchCached)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 2
+	}
+	page, err := fetchTopicPage(topicID)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+
+	article := ""
+	if *withArticle {
+		link := page.Content.ExternalLink
+		if !strings.HasPrefix(link, "http") {
+			fmt.Fprintln(os.Stderr, "gn-text: the topic has no external article")
+			return 1
+		}
+		if article, err = extractArticleText(link); err != nil {
+			fmt.Fprintf(os.Stderr, "gn-text: %s: %v\n", link, err)
+			return 1
+		}
+	}
+
+	if *asJSON {
+		doc := newTopicDocument(page, time.Now())
+		if *noComments {
+			doc.Topic.Comments = []CommentJSON{}
+		}
+		if *withArticle {
+			doc.Topic.ArticleText = &article
+		}
+		if err := writeJSON(w, doc); err != nil {
+			fmt.Fprintln(os.Stderr, "gn-text:", err)
+			return 1
+		}
+		return 0
+	}
+
+	if *width <= 0 {
+		*width = settings.WrapWidth
+	}
+	if *width <= 0 {
+		*width = getTerminalWidth()
+	}
+	color := false
+	if f, ok := w.(*os.File); ok {
+		color = term.IsTerminal(int(f.Fd()))
+	}
+
+	for _, line := range layoutShow(page, article, !*noComments, *width) {
+		fmt.Fprintln(w, terminalText(strings.TrimRight(line, " "), color))
+	}
+	return 0
+}
+
+// resolveTopicID returns the topic ID of arg: a topic ID, a topic URL, or a comment URL,
+// whose page is fetched with fetch to find the topic it belongs to
+func resolveTopicID(arg string, fetch func(string) (string, error)) (string, error) {
+	if _, err := strconv.Atoi(arg); err == nil {
+		return arg, nil
+	}
+
+	u, err := url.Parse(arg)
+	base, _ := url.Parse(geekNewsBaseURL)
+	if err != nil || strings.TrimPrefix(u.Host, "www.") != base.Host {
+		return "", fmt.Errorf("%q is not a topic ID or a GeekNews topic or comment URL", arg)
+	}
+	id := u.Query().Get("id")
+	if id == "" {
+		return "", fmt.Errorf("%q has no topic or comment ID", arg)
+	}
+	switch u.Path {
+	case "/topic":
+		return id, nil
+	case "/comment":
+		html, err := fetch(geekNewsBaseURL + "comment?id=" + id)
+		if err != nil {
+			return "", err
+		}
+		topicURL, _, err := parseGeekNewsCommentTopic(html)
+		if err != nil {
+			return "", err
+		}
+		if topicID := extractTopicID(topicURL); topicID != "" {
+			return topicID, nil
+		}
+		return "", errors.New("the topic of comment " + id + " was not found")
+	}
+	return "", fmt.Errorf("%q is not a topic or comment URL", arg)
+}
+
+// layoutShow lays out a topic for `gn-text show`: the topic content and external link,
+// followed by the article text if given and the comments if wanted
+func layoutShow(page *TopicPage, article string, comments bool, width int) []string {
+	var lines []string
+	if warning := layoutWarning(page.Report); warning != "" {
+		lines = append(lines, warning, "")
+	}
+	separator := strings.Repeat("─", width)
+
+	content := page.Content
+	lines = append(lines, formatTopicContent(content, width)...)
+	if strings.HasPrefix(content.ExternalLink, "http") {
+		lines = append(lines, paint(theme.Link, tview.Escape(content.ExternalLink)), "")
+	}
+
+	if article != "" {
+		text, _ := layoutText(strings.TrimSpace(article), width)
+		lines = append(lines, separator, "")
+		lines = append(lines, text...)
+		lines = append(lines, "")
+	}
+
+	if !comments {
+		return lines
+	}
+	lines = append(lines, separator, "")
+	if len(page.Comments) == 0 {
+		return append(lines, "아직 댓글이 없습니다.")
+	}
+	return append(lines, formatComments(page.Comments, content.Author, width)...)
+}
+
+// terminalText converts tview style tags to ANSI escape sequences, or drops them if color
+// is false, and unescapes the text
+func terminalText(text string, color bool) string {
+	return tagRegex.ReplaceAllStringFunc(text, func(tag string) string {
+		if m := tagRegex.FindStringSubmatch(tag); m[1] != "" {
+			return m[1] + "]"
+		}
+		if !color || strings.HasPrefix(tag, `["`) {
+			return "" // Region tags have no style
+		}
+		return ansiStyle(parseStyle(tag[1 : len(tag)-1]))
+	})
+}
+
+// ansiAttributes are the SGR codes of the text attributes, in code order
+var ansiAttributes = []struct {
+	attr tcell.AttrMask
+	code string
+}{
+	{tcell.AttrBold, "1"},
+	{tcell.AttrDim, "2"},
+	{tcell.AttrItalic, "3"},
+	{tcell.AttrUnderline, "4"},
+	{tcell.AttrBlink, "5"},
+	{tcell.AttrReverse, "7"},
+	{tcell.AttrStrikeThrough, "9"},
+}
+
+// ansiStyle returns the escape sequence that resets the terminal to style. Tags are never
+// nested in the text we lay out, so each one can start from the default style.
+func ansiStyle(style tcell.Style) string {
+	fg, bg, attrs := style.Decompose()
+	codes := []string{"0"}
+	for _, a := range ansiAttributes {
+		if attrs&a.attr != 0 {
+			codes = append(codes, a.code)
+		}
+	}
+	if code := ansiColor(fg, 30); code != "" {
+		codes = append(codes, code)
+	}
+	if code := ansiColor(bg, 40); code != "" {
+		codes = append(codes, code)
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m"
+}
+
+// ansiColor returns the SGR code setting color as a foreground (base 30) or background
+// (base 40) color: the terminal's own palette for named colors, 24-bit for the rest
+func ansiColor(color tcell.Color, base int) string {
+	switch {
+	case !color.Valid():
+		return ""
+	case color.IsRGB():
+		r, g, b := color.RGB()
+		return fmt.Sprintf("%d;2;%d;%d;%d", base+8, r, g, b)
+	}
+	index := int(color - tcell.ColorValid)
+	switch {
+	case index < 8:
+		return strconv.Itoa(base + index)
+	case index < 16:
+		return strconv.Itoa(base + 60 + index - 8)
+	}
+	return fmt.Sprintf("%d;5;%d", base+8, index)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/rivo/tview"
+)
+
+func TestResolveTopicID(t *testing.T) {
+	commentPage := `<div class="comment_row" id="cid50407"><div class="commentinfo">
+		<a href="/user?id=alice">alice</a> <a href="comment?id=50407">1시간전</a>
+		<a href="topic?id=26364">글 제목</a></div></div>`
+	fetch := func(url string) (string, error) {
+		if url == geekNewsBaseURL+"comment?id=50407" {
+			return commentPage, nil
+		}
+		return "", errors.New("offline")
+	}
+
+	tests := map[string]string{
+		"26364":                               "26364",
+		"https://news.hada.io/topic?id=26364": "26364",
+		"https://news.hada.io/topic?go=comments&id=26364": "26364",
+		"http://www.news.hada.io/topic?id=26364":          "26364",
+		"https://news.hada.io/comment?id=50407":           "26364",
+	}
+	for arg, want := range tests {
+		got, err := resolveTopicID(arg, fetch)
+		if err != nil || got != want {
+			t.Errorf("resolveTopicID(%q) = %q, %v; want %q", arg, got, err, want)
+		}
+	}
+
+	for _, arg := range []string{
+		"https://example.com/topic?id=1",
+		"https://news.hada.io/topic",
+		"https://news.hada.io/user?id=alice",
+		"https://news.hada.io/comment?id=1",
+		"hello",
+	} {
+		if got, err := resolveTopicID(arg, fetch); err == nil {
+			t.Errorf("resolveTopicID(%q) = %q; want an error", arg, got)
+		}
+	}
+}
+
+func TestTerminalText(t *testing.T) {
+	text := "see " + paint("yellow::b", tview.Escape("[x] y")) + " and " + paint("#5f87ff::u", "z")
+
+	if got, want := terminalText(text, false), "see [x] y and z"; got != want {
+		t.Errorf("Plain text = %q, want %q", got, want)
+	}
+
+	want := "see \x1b[0;1;93m[x] y\x1b[0m and \x1b[0;4;38;2;95;135;255mz\x1b[0m"
+	if got := terminalText(text, true); got != want {
+		t.Errorf("Colored text = %q, want %q", got, want)
+	}
+}
+
+func TestLayoutShow(t *testing.T) {
+	html, err := os.ReadFile("testdata/geeknews_topic_full.html")
+	if err != nil {
+		t.Fatalf("Failed to read test fixture: %v", err)
+	}
+	page, err := parseTopicPage("1", string(html))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	plain := func(lines []string) string {
+		for i, line := range lines {
+			lines[i] = terminalText(line, false)
+		}
+		return strings.Join(lines, "\n")
+	}
+
+	full := plain(layoutShow(page, "", true, 60))
+	if !strings.Contains(full, page.Content.Title) {
+		t.Errorf("Expected the title in %q", full)
+	}
+	for _, comment := range page.Comments {
+		if !strings.Contains(full, comment.Author+" ") {
+			t.Errorf("Expected the comment by %s", comment.Author)
+		}
+	}
+
+	short := plain(layoutShow(page, "Article text", false, 60))
+	if !strings.Contains(short, "Article text") {
+		t.Errorf("Expected the article text in %q", short)
+	}
+	if len(page.Comments) > 0 && strings.Contains(short, page.Comments[0].Author+" (") {
+		t.Errorf("Expected no comments in %q", short)
+	}
+	for _, line := range strings.Split(full, "\n") {
+		if w := len([]rune(line)); w > 60 && !strings.Contains(line, "http") {
+			t.Errorf("Expected lines wrapped at 60 columns, got %q", line)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/rivo/tview"
+)
+
+// Theme maps semantic styles to tview style tags written as "foreground:background:attributes",
+// e.g. "yellow", "#8a8a8a", "black:yellow" or "white::b". An empty style leaves text unstyled.
+type Theme struct {
+	Base string `yaml:"base,omitempty"` // Theme that custom themes take missing styles from (default dark)
+
+	Title    string `yaml:"title,omitempty"`    // Topic and profile titles, help keys
+	Meta     string `yaml:"meta,omitempty"`     // Author, time and points lines, list secondary text
+	Author   string `yaml:"author,omitempty"`   // Comment authors
+	OP       string `yaml:"op,omitempty"`       // Comments by the topic author
+	Link     string `yaml:"link,omitempty"`     // URLs in topic and comment text
+	Quote    string `yaml:"quote,omitempty"`    // Quoted lines ("> ...")
+	Code     string `yaml:"code,omitempty"`     // `Inline code`
+	Error    string `yaml:"error,omitempty"`    // Errors and layout warnings
+	Match    string `yaml:"match,omitempty"`    // Search matches
+	Selected string `yaml:"selected,omitempty"` // Selected list row
+	Read     string `yaml:"read,omitempty"`     // Titles of opened topics in lists
+	New      string `yaml:"new,omitempty"`      // Comments posted since the last visit
+
+	widgets tview.Theme // Default colors of widgets
+}
+
+var builtinThemes = map[string]Theme{
+	"dark": {
+		Title: "yellow", Meta: "gray", Author: "aqua", OP: "orange::b", Link: "#5f87ff::u",
+		Quote: "green", Code: "silver:#303030", Error: "red", Match: ":yellow", Selected: "black:white",
+		Read: "#767676", New: "lime::b", widgets: tview.Styles,
+	},
+	"light": {
+		Title: "navy::b", Meta: "#5f5f5f", Author: "teal", OP: "purple::b", Link: "blue::u",
+		Quote: "green", Code: "maroon:#e4e4e4", Error: "red", Match: ":yellow", Selected: "::r",
+		Read: "#9e9e9e", New: "green::b", widgets: terminalStyles(tcell.ColorLightGray),
+	},
+	"high-contrast": {
+		Title: "white::b", Meta: "white", Author: "aqua::b", OP: "yellow::b", Link: "aqua::u",
+		Quote: "lime", Code: "black:white", Error: "white:red:b", Match: "black:yellow", Selected: "black:yellow",
+		Read: "silver", New: "black:lime:b", widgets: tview.Styles,
+	},
+}
+
+// monochromeTheme is used when NO_COLOR is set: no colors, only reverse video for selections and matches
+var monochromeTheme = Theme{
+	Match: "::r", Selected: "::r", Read: "::d", New: "::b",
+	widgets: terminalStyles(tcell.ColorDefault),
+}
+
+// terminalStyles returns widget colors that keep the terminal's own foreground and background,
+// with input fields drawn on field
+func terminalStyles(field tcell.Color) tview.Theme {
+	return tview.Theme{
+		PrimitiveBackgroundColor:    tcell.ColorDefault,
+		ContrastBackgroundColor:     field,
+		MoreContrastBackgroundColor: field,
+		BorderColor:                 tcell.ColorDefault,
+		TitleColor:                  tcell.ColorDefault,
+		GraphicsColor:               tcell.ColorDefault,
+		PrimaryTextColor:            tcell.ColorDefault,
+		SecondaryTextColor:          tcell.ColorDefault,
+		TertiaryTextColor:           tcell.ColorDefault,
+		InverseTextColor:            tcell.ColorDefault,
+		ContrastSecondaryTextColor:  tcell.ColorDefault,
+	}
+}
+
+// theme is the active theme, set up by main before any widget is created
+var theme = builtinThemes["dark"]
+
+// theme returns the configured theme, filling unset styles of a custom theme from its base
+func (c *Config) theme() (Theme, error) {
+	custom, ok := c.Themes[c.Theme]
+	if !ok {
+		if t, ok := builtinThemes[c.Theme]; ok {
+			return t, nil
+		}
+		return Theme{}, fmt.Errorf("unknown theme %q (available: %s)", c.Theme, strings.Join(c.themeNames(), ", "))
+	}
+
+	baseName := custom.Base
+	if baseName == "" {
+		baseName = "dark"
+	}
+	base, ok := builtinThemes[baseName]
+	if !ok {
+		return Theme{}, fmt.Errorf("theme %q: unknown base theme %q", c.Theme, baseName)
+	}
+
+	t := base
+	for _, style := range []struct{ from, to *string }{
+		{&custom.Title, &t.Title}, {&custom.Meta, &t.Meta}, {&custom.Author, &t.Author},
+		{&custom.OP, &t.OP}, {&custom.Link, &t.Link}, {&custom.Quote, &t.Quote},
+		{&custom.Code, &t.Code}, {&custom.Error, &t.Error}, {&custom.Match, &t.Match},
+		{&custom.Selected, &t.Selected}, {&custom.Read, &t.Read}, {&custom.New, &t.New},
+	} {
+		if *style.from != "" {
+			*style.to = *style.from
+		}
+	}
+	return t, nil
+}
+
+func (c *Config) themeNames() []string {
+	var names []string
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+	for name := range c.Themes {
+		if _, ok := builtinThemes[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// activeTheme returns the configured theme, or the monochrome theme when NO_COLOR is set
+func (c *Config) activeTheme() (Theme, error) {
+	if os.Getenv("NO_COLOR") != "" {
+		return monochromeTheme, nil
+	}
+	return c.theme()
+}
+
+// apply sets the default widget colors of tview to the theme
+func (t Theme) apply() {
+	tview.Styles = t.widgets
+}
+
+// paint wraps text in style, resetting all colors and attributes afterwards
+func paint(style, text string) string {
+	if style == "" {
+		return text
+	}
+	return "[" + style + "]" + text + "[-:-:-]"
+}
+
+// styleList applies the theme to a list whose secondary text is meta information
+func styleList(list *tview.List) *tview.List {
+	meta, _, _ := parseStyle(theme.Meta).Decompose()
+	return list.
+		SetMainTextColor(theme.widgets.PrimaryTextColor).
+		SetSecondaryTextColor(meta).
+		SetSelectedStyle(parseStyle(theme.Selected))
+}
+
+// parseStyle converts a "foreground:background:attributes" style to a tcell style
+func parseStyle(style string) tcell.Style {
+	result := tcell.StyleDefault
+	parts := strings.SplitN(style, ":", 3)
+	if parts[0] != "" && parts[0] != "-" {
+		result = result.Foreground(tcell.GetColor(parts[0]))
+	}
+	if len(parts) > 1 && parts[1] != "" && parts[1] != "-" {
+		result = result.Background(tcell.GetColor(parts[1]))
+	}
+	if len(parts) > 2 {
+		for _, attr := range parts[2] {
+			switch attr {
+			case 'b':
+				result = result.Bold(true)
+			case 'd':
+				result = result.Dim(true)
+			case 'i':
+				result = result.Italic(true)
+			case 'r':
+				result = result.Reverse(true)
+			case 'u':
+				result = result.Underline(true)
+			case 's':
+				result = result.StrikeThrough(true)
+			case 'l':
+				result = result.Blink(true)
+			}
+		}
+	}
+	return result
+}
+
+var (
+	linkRegex       = regexp.MustCompile(`https?://[^\s()<>\[\]]*[^\s()<>\[\].,;:!?'"]`)
+	inlineCodeRegex = regexp.MustCompile("`[^`]+`")
+)
+
+// styleBodyLine escapes a wrapped line of topic or comment text and styles quotes, links
+// and inline code in it. indent is left as is.
+func styleBodyLine(indent, line string) string {
+	text := strings.TrimPrefix(line, indent)
+	if strings.HasPrefix(text, ">") {
+		return indent + paint(theme.Quote, tview.Escape(text))
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, loc := range styledSpans(text) {
+		b.WriteString(tview.Escape(text[last:loc[0]]))
+		style := theme.Link
+		if text[loc[0]] == '`' {
+			style = theme.Code
+		}
+		b.WriteString(paint(style, tview.Escape(text[loc[0]:loc[1]])))
+		last = loc[1]
+	}
+	b.WriteString(tview.Escape(text[last:]))
+	return indent + b.String()
+}
+
+// styledSpans returns the non-overlapping link and inline code spans of text in order
+func styledSpans(text string) [][]int {
+	spans := append(inlineCodeRegex.FindAllStringIndex(text, -1), linkRegex.FindAllStringIndex(text, -1)...)
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+
+	var result [][]int
+	end := 0
+	for _, span := range spans {
+		if span[0] >= end {
+			result = append(result, span)
+			end = span[1]
+		}
+	}
+	return result
+}
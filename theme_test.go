@@ -0,0 +1,125 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell/v2"
+)
+
+func TestConfigTheme(t *testing.T) {
+	cfg := defaultConfig()
+	cfg.Theme = "solarized"
+	cfg.Themes = map[string]Theme{"solarized": {Base: "light", Title: "#b58900::b"}}
+
+	th, err := cfg.theme()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if th.Title != "#b58900::b" {
+		t.Errorf("Expected custom title style, got %q", th.Title)
+	}
+	if th.Link != builtinThemes["light"].Link {
+		t.Errorf("Expected link style from the base theme, got %q", th.Link)
+	}
+
+	cfg.Theme = "missing"
+	if _, err := cfg.theme(); err == nil || !strings.Contains(err.Error(), "high-contrast") {
+		t.Errorf("Expected unknown theme error listing themes, got %v", err)
+	}
+
+	cfg.Theme = "broken"
+	cfg.Themes["broken"] = Theme{Base: "sepia"}
+	if _, err := cfg.theme(); err == nil {
+		t.Error("Expected error for unknown base theme")
+	}
+}
+
+func TestNoColorTheme(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	th, err := defaultConfig().activeTheme()
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	saved := theme
+	theme = th
+	defer func() { theme = saved }()
+
+	lines := formatTopicContent(&TopicContent{
+		Title:  "제목",
+		Author: "user1",
+		Body:   "링크 https://example.com 와 `code`",
+	}, 60)
+	text := strings.Join(lines, "\n")
+	if loc := styleTagRegex.FindStringIndex(text); loc != nil {
+		t.Errorf("Expected no style tags with NO_COLOR, found %q in %q", text[loc[0]:loc[1]], text)
+	}
+}
+
+func TestStyleBodyLine(t *testing.T) {
+	saved := theme
+	theme = Theme{Link: "blue", Code: "red", Quote: "green"}
+	defer func() { theme = saved }()
+
+	tests := []struct {
+		indent, line, expected string
+	}{
+		{"", "see https://example.com/a.", "see [blue]https://example.com/a[-:-:-]."},
+		{"| ", "| use `go vet` now", "| use [red]`go vet`[-:-:-] now"},
+		{"  ", "  > quoted [text]", "  [green]> quoted [text[][-:-:-]"},
+		{"", "array[0] stays", "array[0[] stays"},
+		{"", "plain [red] text", "plain [red[] text"},
+	}
+	for _, tt := range tests {
+		if got := styleBodyLine(tt.indent, tt.line); got != tt.expected {
+			t.Errorf("styleBodyLine(%q, %q) = %q, expected %q", tt.indent, tt.line, got, tt.expected)
+		}
+	}
+}
+
+func TestFormatCommentsMarksOP(t *testing.T) {
+	saved := theme
+	theme = Theme{Author: "aqua", OP: "orange"}
+	defer func() { theme = saved }()
+
+	lines := formatComments([]Comment{{Author: "op"}, {Author: "other"}}, "op", 60)
+	text := strings.Join(lines, "\n")
+	if !strings.Contains(text, "[orange]op[-:-:-]") || !strings.Contains(text, "[aqua]other[-:-:-]") {
+		t.Errorf("Expected OP and author styles, got %q", text)
+	}
+}
+
+func TestParseStyle(t *testing.T) {
+	fg, bg, attrs := parseStyle("black:yellow:bu").Decompose()
+	if fg != tcell.ColorBlack || bg != tcell.ColorYellow || attrs != tcell.AttrBold|tcell.AttrUnderline {
+		t.Errorf("Unexpected style: %v %v %v", fg, bg, attrs)
+	}
+
+	fg, bg, attrs = parseStyle("::r").Decompose()
+	if fg != tcell.ColorDefault || bg != tcell.ColorDefault || attrs != tcell.AttrReverse {
+		t.Errorf("Unexpected style: %v %v %v", fg, bg, attrs)
+	}
+}
+
+func TestHighlightMatchesRestoresStyle(t *testing.T) {
+	saved := theme
+	theme = Theme{Match: ":yellow"}
+	defer func() { theme = saved }()
+
+	highlighted, _ := highlightMatches("[gray]user1 · 1시간전[-:-:-]", "user1")
+	expected := `[gray]["search-0"][:yellow]user1[-:-:-][gray][""] · 1시간전[-:-:-]`
+	if highlighted != expected {
+		t.Errorf("Expected %q, got %q", expected, highlighted)
+	}
+}
+
+func TestSanitizeInlineCode(t *testing.T) {
+	result := sanitize("<p>Run <code>go test</code> first</p><pre><code>x := 1</code></pre>")
+	if !strings.Contains(result, "Run `go test` first") {
+		t.Errorf("Expected inline code in backticks, got %q", result)
+	}
+	if strings.Contains(result, "`x := 1`") {
+		t.Errorf("Expected code blocks without backticks, got %q", result)
+	}
+}
@@ -6,151 +6,273 @@ import (
 	"runtime"
 	"strings"
 
-	"github.com/gdamore/tcell/v2"
-	"github.com/gelembjuk/articletext"
 	"github.com/rivo/tview"
 )
 
-func createArticleList(articles []Article) *tview.List {
-	list := tview.NewList().ShowSecondaryText(true).SetSecondaryTextColor(tcell.ColorGray)
-	for _, article := range articles {
-		// Display title and domain only (no comment count from RSS)
-		list.AddItem(article.Title, article.Domain, 0, nil)
+// fillList shows the articles matching the filter, leaving out read ones while they are hidden
+func (s *session) fillList() {
+	query := ""
+	if s.filter.isActive() {
+		query = s.filter.input.GetText()
 	}
 
-	return list
+	s.rows = s.rows[:0]
+	s.list.Clear()
+	for i, article := range s.articles {
+		if !matchesFilter(article, query) {
+			continue
+		}
+		if s.hideRead && reads.seen(extractTopicID(article.CommentsLink)) {
+			continue
+		}
+		s.rows = append(s.rows, i)
+		mainText, secondaryText := listItem(article)
+		s.list.AddItem(mainText, secondaryText, 0, nil)
+	}
+	s.updateCounter()
 }
 
-func fetchAndGenerateList() (*tview.List, []Article, error) {
+func fetchArticles() ([]Article, error) {
 	rssContent, err := fetchWebpage(geekNewsRSSURL)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	articles, err := parseGeekNewsRSS(rssContent)
-	if err != nil {
-		return nil, nil, err
-	}
-
-	list := createArticleList(articles)
-	return list, articles, nil
-}
-
-func createInputHandler(app *tview.Application, list *tview.List, articles []Article, pages *tview.Pages) func(event *tcell.EventKey) *tcell.EventKey {
-	// Store articles in closure for refresh
-	currentArticles := articles
-
-	return func(event *tcell.EventKey) *tcell.EventKey {
-		switch event.Key() {
-		case tcell.KeyCtrlC:
-			app.Stop()
-			return nil
-		case tcell.KeyRight:
-			nextPage(pages, app, currentArticles, list)
-			return nil
-		case tcell.KeyLeft:
-			backPage(pages)
-			return nil
-		case tcell.KeyRune:
-			switch event.Rune() {
-			case 'q':
-				app.Stop()
-				return nil
-			case 'j':
-				return tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone)
-			case 'k':
-				return tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone)
-			case 'l':
-				nextPage(pages, app, currentArticles, list)
-				return nil
-			case 'h':
-				backPage(pages)
-				return nil
-			case ' ':
-				openArticleInBrowser(currentArticles[list.GetCurrentItem()])
-				return nil
-			case 'c':
-				openCommentsInBrowser(currentArticles[list.GetCurrentItem()])
-				return nil
-			case 'r':
-				refreshedList, newArticles, err := fetchAndGenerateList()
-				if err != nil {
-					// Show error but don't crash
-					return nil
-				}
-				currentArticles = newArticles
-				pages.AddPage("homepage", refreshedList, true, false)
-				app.SetRoot(refreshedList, true).Run()
-			}
-		}
+	return parseGeekNewsRSS(rssContent)
+}
 
-		return event
+// session holds the widgets and navigation state shared by the input handler and views
+type session struct {
+	app       *tview.Application
+	root      *tview.Flex     // Status bar above the pages
+	statusBar *tview.Flex     // Status message and unread counter (top of screen)
+	status    *tview.TextView // Status message
+	counter   *tview.TextView // Unread articles
+	pages     *tview.Pages
+	home      *tview.Flex // Homepage layout: the article list and preview and, while filtering, the filter input
+	list      *tview.List
+	articles  []Article
+	rows      []int // Index into articles for each list row
+	hideRead  bool  // Leave opened and read articles out of the list
+	filter    *listFilter
+	keymap    []action
+	pending   []string // Keys typed so far of a multi-key sequence
+
+	textPages map[string]*textPage // Comments and article pages by page name
+
+	topic       Article      // Topic shown on the comments and article pages
+	page        *TopicPage   // Parsed topic page for topic, if it loaded
+	user        *userView    // Last opened user view
+	saved       *savedView   // Bookmark list, once opened
+	historyView *historyView // Reading history, once opened
+	preview     *previewPane // Preview of the selected topic beside the article list
+	stack       []navEntry   // Views shown, the article list first
+	pos         int          // Index of the current view in stack; later views can be gone forward to
+}
+
+// newSession creates the pages for the article list
+func newSession(app *tview.Application, articles []Article, keymap []action) *session {
+	s := &session{
+		app:       app,
+		pages:     tview.NewPages(),
+		list:      styleList(tview.NewList().ShowSecondaryText(true)),
+		articles:  articles,
+		keymap:    keymap,
+		textPages: make(map[string]*textPage),
 	}
+	s.preview = s.newPreviewPane()
+	s.home = tview.NewFlex().SetDirection(tview.FlexRow).AddItem(&splitLayout{s.preview.split, s}, 0, 1, true)
+	s.listMouse(s.list)
+	s.pages.AddPage("homepage", s.home, true, true)
+	s.stack = []navEntry{{name: "homepage", item: s.home}}
+
+	s.status = tview.NewTextView().SetDynamicColors(true)
+	s.counter = tview.NewTextView().SetTextAlign(tview.AlignRight)
+	s.statusBar = tview.NewFlex().
+		AddItem(s.status, 0, 1, false).
+		AddItem(s.counter, 0, 0, false)
+	s.root = tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(s.statusBar, 1, 0, false).
+		AddItem(s.pages, 0, 1, true)
+
+	s.fillList()
+	return s
 }
 
-func backPage(pages *tview.Pages) {
-	currentPage, _ := pages.GetFrontPage()
-	if currentPage == "comments" {
-		pages.SwitchToPage("homepage")
+// setStatus shows text in the status bar
+func (s *session) setStatus(text string) {
+	s.status.SetText(text)
+}
+
+// refresh drops cached pages, re-fetches the article list and goes back to it
+func (s *session) refresh() {
+	if cache != nil {
+		cache.invalidate()
+	}
+	newArticles, err := fetchArticles()
+	if err != nil {
+		// Show error but don't crash
+		s.setStatus(paint(theme.Error, "새로고침 실패: "+tview.Escape(err.Error())))
+		return
 	}
-	if currentPage == "article" {
-		pages.SwitchToPage("comments")
+	s.clearFilter()
+	s.articles = newArticles
+	s.fillList()
+	s.homePage()
+}
+
+// frontPage returns the name of the page being shown
+func (s *session) frontPage() string {
+	name, _ := s.pages.GetFrontPage()
+	return name
+}
+
+func (s *session) nextPage() {
+	switch s.frontPage() {
+	case "homepage":
+		if article, ok := s.selectedArticle(); ok {
+			s.openComments(article)
+		}
+	case "comments":
+		s.openArticle(s.topic)
+	case "user":
+		if article, ok := s.user.selectedTopic(); ok {
+			s.openComments(article)
+		}
+	case "authors":
+		if s.user != nil {
+			s.user.pickSelected()
+		}
+	case "saved":
+		if article, ok := s.saved.selectedTopic(); ok {
+			s.openComments(article)
+		}
+	case "history":
+		s.historyView.open()
 	}
 }
 
-func nextPage(pages *tview.Pages, app *tview.Application, articles []Article, list *tview.List) {
-	currentPage, _ := pages.GetFrontPage()
-	if currentPage == "comments" {
-		openArticle(app, articles[list.GetCurrentItem()], pages)
-	} else {
-		openComments(app, articles[list.GetCurrentItem()], pages)
+// selectedArticle returns the topic the current page is about
+func (s *session) selectedArticle() (Article, bool) {
+	switch s.frontPage() {
+	case "homepage":
+		return s.listArticle(s.list.GetCurrentItem())
+	case "user":
+		return s.user.selectedTopic()
+	case "saved":
+		return s.saved.selectedTopic()
+	case "history":
+		if e, ok := s.historyView.selected(); ok {
+			return e.article(), true
+		}
+		return Article{}, false
+	default:
+		return s.topic, s.topic.CommentsLink != ""
 	}
 }
 
-func openComments(app *tview.Application, article Article, pages *tview.Pages) {
+func (s *session) openComments(article Article) {
+	s.topic = article
+	s.page = nil
+
 	topicID := extractTopicID(article.CommentsLink)
 	if topicID == "" {
-		displayComments(app, pages, "토픽 ID를 찾을 수 없습니다.")
+		s.displayComments("토픽 ID를 찾을 수 없습니다.")
 		return
 	}
 
-	commentLines := fetchGeekNewsComments(topicID)
-	commentsText := strings.Join(commentLines, "\n")
+	page, err := fetchTopicPage(topicID)
+	offline := false
+	if err != nil {
+		page, err = savedTopicPage(topicID, err)
+		offline = err == nil
+	}
+	if err != nil {
+		s.displayComments("페이지를 불러오는데 실패했습니다: " + err.Error())
+		return
+	}
+	s.page = page
+	if page.Content != nil {
+		// Topics opened from a link are only known by their URL
+		if article.Title == "" {
+			article.Title = page.Content.Title
+		}
+		if article.Link == "" && strings.HasPrefix(page.Content.ExternalLink, "http") {
+			article.Link = page.Content.ExternalLink
+			article.Domain = extractDomainFromURL(article.Link)
+		}
+		s.topic = article
+	}
+	page.unseen = reads.unseenComments(topicID, page.Comments)
+
+	s.showText("comments", func(width int) ([]string, []int) {
+		return layoutTopicPage(page, width)
+	})
+	s.markOpened(article)
+	s.saveReads(reads.markComments(topicID, page.Comments))
+	s.recordView("comments", article)
 
-	displayComments(app, pages, commentsText)
+	var status []string
+	if offline {
+		status = append(status, fmt.Sprintf("오프라인: %s에 저장한 사본입니다", savedTime(article)))
+	}
+	if n := len(page.unseen); n > 0 {
+		status = append(status, fmt.Sprintf("새 댓글 %d개 (']' 키로 이동)", n))
+	}
+	if len(status) > 0 {
+		s.setStatus(strings.Join(status, " · "))
+	}
 }
 
-func openArticle(app *tview.Application, article Article, pages *tview.Pages) {
+func (s *session) openArticle(article Article) {
 	// Try to get external link - first check if we have it cached
 	externalLink := article.Link
+	if externalLink == "" && s.page != nil && s.page.Content != nil {
+		externalLink = s.page.Content.ExternalLink
+	}
 
 	// If no external link, fetch from topic page
 	if externalLink == "" {
 		var err error
-		externalLink, err = fetchExternalLink(article.CommentsLink)
+		var report *ParseReport
+		externalLink, report, err = fetchExternalLink(article.CommentsLink)
 		if err != nil || externalLink == "" {
-			displayArticle(app, pages, "기사 링크를 찾을 수 없습니다. 'c' 키를 눌러 GeekNews 페이지에서 확인하세요.")
+			if s.showSavedArticle(article) {
+				return
+			}
+			text := "기사 링크를 찾을 수 없습니다. 'c' 키를 눌러 GeekNews 페이지에서 확인하세요."
+			if warning := layoutWarning(report); warning != "" {
+				text = warning + "\n\n" + text
+			}
+			s.displayArticle(text)
 			return
 		}
 	}
 
 	// Check if it's an internal GeekNews link (Ask GN style posts)
 	if !strings.HasPrefix(externalLink, "http") {
-		displayArticle(app, pages, "이 게시물은 외부 링크가 없습니다. 'c' 키를 눌러 GeekNews에서 확인하세요.")
+		s.displayArticle("이 게시물은 외부 링크가 없습니다. 'c' 키를 눌러 GeekNews에서 확인하세요.")
 		return
 	}
 
 	articleText := getArticleTextFromLink(externalLink)
+	if articleText == "" && s.showSavedArticle(article) {
+		return
+	}
 	if articleText == "" {
-		displayArticle(app, pages, "기사 내용을 추출할 수 없습니다. 'space' 키를 눌러 브라우저에서 열어보세요.")
+		s.displayArticle("기사 내용을 추출할 수 없습니다. 'space' 키를 눌러 브라우저에서 열어보세요.")
 		return
 	}
 
-	displayArticle(app, pages, articleText)
+	s.showText("article", func(width int) ([]string, []int) {
+		return layoutText(articleText, width)
+	})
+	s.markRead(article)
+	s.recordView("article", article)
 }
 
 func getArticleTextFromLink(url string) string {
-	article, err := articletext.GetArticleTextFromUrl(url)
+	article, err := extractArticleText(url)
 	if err != nil {
 		fmt.Printf("기사 파싱 실패 %s, %v\n", url, err)
 		return ""
@@ -158,24 +280,37 @@ func getArticleTextFromLink(url string) string {
 	return article
 }
 
-func displayArticle(app *tview.Application, pages *tview.Pages, text string) {
-	articleTextView := tview.NewTextView().
-		SetText(text).
-		SetDynamicColors(true).
-		SetScrollable(true)
+func (s *session) displayArticle(text string) {
+	s.displayText("article", text)
+}
 
-	pages.AddPage("article", articleTextView, true, true)
-	app.SetRoot(pages, true)
+func (s *session) displayComments(text string) {
+	s.displayText("comments", text)
 }
 
-func displayComments(app *tview.Application, pages *tview.Pages, text string) {
-	commentsTextView := tview.NewTextView().
-		SetText(text).
-		SetDynamicColors(true).
-		SetScrollable(true)
+// displayText shows a message on a searchable text page
+func (s *session) displayText(name string, text string) {
+	s.showText(name, staticText(text))
+}
 
-	pages.AddPage("comments", commentsTextView, true, true)
-	app.SetRoot(pages, true)
+// showText shows the text laid out by render on a searchable text page
+func (s *session) showText(name string, render renderFunc) {
+	p := newTextPage(render)
+	p.topicID = extractTopicID(s.topic.CommentsLink)
+	if pos, ok := history.position(name, p.topicID); ok {
+		p.resume = &pos
+	}
+	p.textMouse(false, s.followLink, func(row int) bool {
+		if name != "comments" {
+			return false
+		}
+		start := s.foldAt(row)
+		if start >= 0 {
+			s.expandFolds(start)
+		}
+		return start >= 0
+	})
+	s.pushView(navEntry{name: name, item: p.layout, text: p})
 }
 
 // openArticleInBrowser opens the article's external link in the browser
@@ -185,7 +320,7 @@ func openArticleInBrowser(article Article) {
 	// If no cached external link, fetch it
 	if externalLink == "" {
 		var err error
-		externalLink, err = fetchExternalLink(article.CommentsLink)
+		externalLink, _, err = fetchExternalLink(article.CommentsLink)
 		if err != nil || externalLink == "" {
 			// Fall back to opening the topic page
 			openURL(article.CommentsLink)
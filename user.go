@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
+)
+
+// userView shows a user's profile with their submitted topics and recent comments
+type userView struct {
+	s      *session
+	page   *UserPage
+	topics []Article // Topic opened by each list item
+	list   *tview.List
+
+	authors    []pageAuthor // Candidates shown by the author picker
+	authorList *tview.List
+}
+
+// pageAuthor is an author on the current page: the name shown and the account ID
+type pageAuthor struct {
+	Name string
+	ID   string
+}
+
+// openAuthor opens the user view for the author of the current topic.
+// On the comments page it first asks which author to open.
+func (s *session) openAuthor() {
+	switch s.frontPage() {
+	case "homepage":
+		if article, ok := s.selectedArticle(); ok && article.AuthorID != "" {
+			s.openUser(article.AuthorID)
+		}
+	case "comments", "article":
+		authors := s.pageAuthors()
+		if len(authors) == 1 {
+			s.openUser(authors[0].ID)
+			return
+		}
+		if len(authors) > 1 {
+			s.showAuthorPicker(authors)
+		}
+	case "user":
+		if s.user != nil {
+			if article, ok := s.user.selectedTopic(); ok && article.AuthorID != "" {
+				s.openUser(article.AuthorID)
+			}
+		}
+	}
+}
+
+// pageAuthors returns the topic author followed by the comment authors, without duplicates
+func (s *session) pageAuthors() []pageAuthor {
+	var authors []pageAuthor
+	seen := make(map[string]bool)
+	add := func(author pageAuthor) {
+		if author.ID != "" && !seen[author.ID] {
+			seen[author.ID] = true
+			authors = append(authors, author)
+		}
+	}
+
+	add(s.topicAuthor())
+	if s.page != nil {
+		for _, comment := range s.page.Comments {
+			add(pageAuthor{Name: comment.Author, ID: comment.AuthorID})
+		}
+	}
+	return authors
+}
+
+// topicAuthor returns the author of the current topic, preferring the parsed topic page
+func (s *session) topicAuthor() pageAuthor {
+	if s.page != nil && s.page.Content != nil && s.page.Content.AuthorID != "" {
+		return pageAuthor{Name: s.page.Content.Author, ID: s.page.Content.AuthorID}
+	}
+	return pageAuthor{Name: s.topic.Author, ID: s.topic.AuthorID}
+}
+
+// showAuthorPicker lists the authors on the current page to choose from
+func (s *session) showAuthorPicker(authors []pageAuthor) {
+	if s.user == nil {
+		s.user = &userView{s: s}
+	}
+	s.user.authors = authors
+
+	list := styleList(tview.NewList().ShowSecondaryText(true))
+	topicAuthor := s.topicAuthor()
+	for _, author := range authors {
+		secondary := "댓글 작성자"
+		if author.ID == topicAuthor.ID {
+			secondary = "글 작성자"
+		}
+		if author.Name != author.ID {
+			secondary += " · " + author.ID
+		}
+		list.AddItem(tview.Escape(author.Name), secondary, 0, nil)
+	}
+	list.SetSelectedFunc(func(int, string, string, rune) {
+		s.user.pickSelected()
+	})
+	list.SetTitle(" 작성자 선택 ").SetBorder(true)
+	s.listMouse(list)
+	s.user.authorList = list
+
+	s.pushView(navEntry{name: "authors", item: list, overlay: true})
+}
+
+// pickSelected opens the author highlighted in the author picker
+func (v *userView) pickSelected() {
+	if v.authorList == nil {
+		return
+	}
+	index := v.authorList.GetCurrentItem()
+	if index >= 0 && index < len(v.authors) {
+		v.s.openUser(v.authors[index].ID)
+	}
+}
+
+// openUser fetches a user's pages and shows the user view
+func (s *session) openUser(userID string) {
+	page, err := fetchUserPage(userID)
+	if err != nil {
+		text := tview.NewTextView().SetText("사용자 정보를 불러오는데 실패했습니다: " + err.Error())
+		s.showPage("user", text)
+		return
+	}
+
+	v := &userView{s: s, page: page}
+	if s.user != nil {
+		v.authors, v.authorList = s.user.authors, s.user.authorList
+	}
+	s.user = v
+
+	header := tview.NewTextView().
+		SetText(strings.Join(formatUserProfile(page), "\n")).
+		SetDynamicColors(true)
+
+	v.list = styleList(tview.NewList().ShowSecondaryText(true))
+	for _, topic := range page.Topics {
+		secondary := "글 · " + topic.Domain
+		if topic.Points != "" {
+			secondary += " · " + topic.Points + "P"
+		}
+		title := tview.Escape(topic.Title)
+		if reads.seen(extractTopicID(topic.CommentsLink)) {
+			title = paint(theme.Read, title)
+		}
+		v.list.AddItem(title, secondary, 0, nil)
+		v.topics = append(v.topics, topic)
+	}
+	for _, comment := range page.Comments {
+		v.list.AddItem(commentSnippet(comment.Body, 80), "댓글 · "+comment.TopicTitle+" · "+comment.Time, 0, nil)
+		v.topics = append(v.topics, Article{
+			Title:        comment.TopicTitle,
+			CommentsLink: comment.TopicLink,
+			Domain:       "news.hada.io",
+		})
+	}
+	if len(v.topics) == 0 {
+		v.list.AddItem("내용 없음", "", 0, nil)
+	}
+	v.list.SetSelectedFunc(func(int, string, string, rune) {
+		if article, ok := v.selectedTopic(); ok {
+			s.openComments(article)
+		}
+	})
+	s.listMouse(v.list)
+
+	layout := tview.NewFlex().SetDirection(tview.FlexRow).
+		AddItem(header, len(strings.Split(header.GetText(false), "\n"))+1, 0, false).
+		AddItem(v.list, 0, 1, true)
+
+	s.showPage("user", layout)
+}
+
+// selectedTopic returns the topic behind the highlighted user view item
+func (v *userView) selectedTopic() (Article, bool) {
+	if v == nil || v.list == nil {
+		return Article{}, false
+	}
+	index := v.list.GetCurrentItem()
+	if index < 0 || index >= len(v.topics) || v.topics[index].CommentsLink == "" {
+		return Article{}, false
+	}
+	return v.topics[index], true
+}
+
+// formatUserProfile formats the profile header of the user view
+func formatUserProfile(page *UserPage) []string {
+	profile := page.Profile
+	var lines []string
+
+	if warning := layoutWarning(page.Report); warning != "" {
+		lines = append(lines, warning)
+	}
+
+	lines = append(lines, paint(theme.Title, tview.Escape(profile.ID)))
+
+	var meta []string
+	if profile.Karma != "" {
+		meta = append(meta, "karma "+profile.Karma)
+	}
+	if profile.Created != "" {
+		meta = append(meta, "가입 "+profile.Created)
+	}
+	meta = append(meta, fmt.Sprintf("글 %d개", len(page.Topics)), fmt.Sprintf("댓글 %d개", len(page.Comments)))
+	lines = append(lines, paint(theme.Meta, tview.Escape(strings.Join(meta, " · "))))
+
+	if profile.About != "" {
+		lines = append(lines, wrapTextWithRuneWidth(tview.Escape(profile.About), wrapWidth(), "")...)
+	}
+
+	return lines
+}
+
+// commentSnippet returns the first line of a comment body, cut to maxWidth cells
+func commentSnippet(body string, maxWidth int) string {
+	line := strings.TrimSpace(strings.SplitN(strings.TrimSpace(body), "\n", 2)[0])
+	if line == "" {
+		return "[삭제됨]"
+	}
+	return runewidth.Truncate(line, maxWidth, "…")
+}
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// WatchConfig configures `gn-text watch`
+type WatchConfig struct {
+	Interval int         `yaml:"interval"` // Seconds between polls of the feed
+	Command  string      `yaml:"command"`  // Run for each match with the alert as JSON on stdin; matches are printed if empty
+	Rules    []WatchRule `yaml:"rules"`
+}
+
+// WatchRule matches topics by keyword, regular expression or link domain. A topic matches
+// the rule if it matches any of them.
+type WatchRule struct {
+	Name     string   `yaml:"name"`
+	Keywords []string `yaml:"keywords,omitempty"` // Case-insensitive, in the title or summary
+	Regex    string   `yaml:"regex,omitempty"`    // Matched against the title and summary
+	Domains  []string `yaml:"domains,omitempty"`  // Link domains, subdomains included
+
+	re *regexp.Regexp
+}
+
+// compile checks the rules and compiles their regular expressions
+func (c *WatchConfig) compile() error {
+	for i := range c.Rules {
+		r := &c.Rules[i]
+		if r.Name == "" {
+			r.Name = fmt.Sprintf("rule %d", i+1)
+		}
+		if len(r.Keywords) == 0 && r.Regex == "" && len(r.Domains) == 0 {
+			return fmt.Errorf("watch rule %q has no keywords, regex or domains", r.Name)
+		}
+		if r.Regex == "" {
+			continue
+		}
+		re, err := regexp.Compile(r.Regex)
+		if err != nil {
+			return fmt.Errorf("watch rule %q: %w", r.Name, err)
+		}
+		r.re = re
+	}
+	return nil
+}
+
+// matches reports whether the article matches the rule
+func (r *WatchRule) matches(article Article) bool {
+	text := article.Title + "\n" + article.Summary
+	lower := strings.ToLower(text)
+	for _, keyword := range r.Keywords {
+		if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	if r.re != nil && r.re.MatchString(text) {
+		return true
+	}
+	domain := strings.ToLower(strings.TrimPrefix(listField(article, "domain"), "www."))
+	if listField(article, "link") == "" {
+		domain = "" // Posts without an external link are on news.hada.io itself
+	}
+	for _, d := range r.Domains {
+		d = strings.ToLower(strings.TrimPrefix(d, "www."))
+		if domain != "" && (domain == d || strings.HasSuffix(domain, "."+d)) {
+			return true
+		}
+	}
+	return false
+}
+
+// watchLogKeep is how long alerted topics are remembered, well beyond their time in the feed
+const watchLogKeep = 30 * 24 * time.Hour
+
+// watchLog is the set of topics already alerted on, kept across restarts
+type watchLog struct {
+	Alerted map[string]time.Time `json:"alerted"` // By topic ID
+
+	path string // File the log is saved to; not saved if empty
+}
+
+func watchLogPath() (string, error) {
+	return statePath("watch.json")
+}
+
+// loadWatchLog reads the watch log saved at path, forgetting old topics
+func loadWatchLog(path string) (*watchLog, error) {
+	l := &watchLog{Alerted: make(map[string]time.Time), path: path}
+	if err := loadState(path, l); err != nil {
+		return nil, err
+	}
+	if l.Alerted == nil {
+		l.Alerted = make(map[string]time.Time)
+	}
+	for id, t := range l.Alerted {
+		if time.Since(t) > watchLogKeep {
+			delete(l.Alerted, id)
+		}
+	}
+	return l, nil
+}
+
+func (l *watchLog) save() error {
+	if l.path == "" {
+		return nil
+	}
+	return saveState(l.path, l)
+}
+
+// watchAlert is a topic matching a rule
+type watchAlert struct {
+	Rule    string
+	Article Article
+}
+
+// matchNew returns an alert for each article not alerted on yet that matches a rule,
+// naming the first rule it matches
+func (l *watchLog) matchNew(rules []WatchRule, articles []Article) []watchAlert {
+	var alerts []watchAlert
+	for _, article := range articles {
+		id := extractTopicID(article.CommentsLink)
+		if _, done := l.Alerted[id]; done || id == "" {
+			continue
+		}
+		for i := range rules {
+			if rules[i].matches(article) {
+				alerts = append(alerts, watchAlert{Rule: rules[i].Name, Article: article})
+				break
+			}
+		}
+	}
+	return alerts
+}
+
+// fetchWatchTopics fetches the feed, with links and domains from the newest topics page
+func fetchWatchTopics(fetch func(string) (string, error)) ([]Article, error) {
+	feed, err := fetch(geekNewsRSSURL)
+	if err != nil {
+		return nil, err
+	}
+	articles, err := parseGeekNewsRSS(feed)
+	if err != nil {
+		return nil, err
+	}
+	if html, err := fetch(geekNewsBaseURL + "new"); err == nil {
+		if topics, _, err := parseGeekNewsTopicList(html); err == nil {
+			enrichArticles(articles, topics)
+		}
+	}
+	return articles, nil
+}
+
+// newWatchAlertDocument returns the JSON document of an alert, written to the watch command
+func newWatchAlertDocument(alert watchAlert, now time.Time) WatchAlertDocument {
+	return WatchAlertDocument{
+		SchemaVersion: schemaVersion,
+		GeneratedAt:   formatJSONTime(now),
+		Rule:          alert.Rule,
+		Article:       articleJSON(alert.Article),
+	}
+}
+
+// watchCommandTimeout bounds how long the watch command may run for one alert
+const watchCommandTimeout = time.Minute
+
+// runWatchCommand runs command with the alert as JSON on stdin. The rule and the topic's ID,
+// title and URL are also set in GN_TEXT_WATCH_RULE and GN_TEXT_TOPIC_{ID,TITLE,URL}.
+func runWatchCommand(command string, alert watchAlert, stdout, stderr io.Writer) error {
+	var input bytes.Buffer
+	if err := writeJSON(&input, newWatchAlertDocument(alert, time.Now())); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), watchCommandTimeout)
+	defer cancel()
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.CommandContext(ctx, "cmd", "/c", command)
+	} else {
+		cmd = exec.CommandContext(ctx, "sh", "-c", command)
+	}
+	cmd.Stdin = &input
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+	cmd.Env = append(os.Environ(),
+		"GN_TEXT_WATCH_RULE="+alert.Rule,
+		"GN_TEXT_TOPIC_ID="+extractTopicID(alert.Article.CommentsLink),
+		"GN_TEXT_TOPIC_TITLE="+alert.Article.Title,
+		"GN_TEXT_TOPIC_URL="+alert.Article.CommentsLink,
+	)
+	return cmd.Run()
+}
+
+// watcher polls the feed and alerts on new matching topics
+type watcher struct {
+	config WatchConfig
+	log    *watchLog
+	fetch  func(string) (string, error)
+	out    io.Writer
+}
+
+// poll fetches the feed once and alerts on the new matches. A topic whose command fails
+// is not remembered, so that it is alerted on again at the next poll.
+func (w *watcher) poll() error {
+	articles, err := fetchWatchTopics(w.fetch)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, alert := range w.log.matchNew(w.config.Rules, articles) {
+		if w.config.Command == "" {
+			fmt.Fprintf(w.out, "[%s] %s\n", alert.Rule, strings.Join(listValues(alert.Article, defaultListFields), "  "))
+		} else if err := runWatchCommand(w.config.Command, alert, w.out, os.Stderr); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", w.config.Command, alert.Article.CommentsLink, err))
+			continue
+		}
+		w.log.Alerted[extractTopicID(alert.Article.CommentsLink)] = time.Now()
+	}
+	if err := w.log.save(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// runWatch implements `gn-text watch`, which polls the feed for topics matching the watch rules
+func runWatch(args []string, w io.Writer) int {
+	fs := flag.NewFlagSet("watch", flag.ContinueOnError)
+	fs.SetOutput(w)
+	once := fs.Bool("once", false, "Poll once and exit")
+	interval := fs.Int("interval", settings.Watch.Interval, "Seconds between polls")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if len(settings.Watch.Rules) == 0 {
+		fmt.Fprintf(os.Stderr, "gn-text: no watch rules in %s (see README, Watching for Topics)\n", settings.Path)
+		return 2
+	}
+	if *interval < 1 {
+		fmt.Fprintln(os.Stderr, "gn-text: --interval must be at least 1")
+		return 2
+	}
+
+	path, err := watchLogPath()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "gn-text:", err)
+		return 1
+	}
+	alerted, err := loadWatchLog(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gn-text: %s: %v\n", path, err)
+		return 1
+	}
+
+	// Fetch past the page cache: a cached feed would hide new topics for its TTL
+	watcher := &watcher{config: settings.Watch, log: alerted, fetch: fetchWebpage, out: w}
+	for {
+		if err := watcher.poll(); err != nil {
+			fmt.Fprintln(os.Stderr, "gn-text:", err)
+			if *once {
+				return 1
+			}
+		}
+		if *once {
+			return 0
+		}
+		time.Sleep(time.Duration(*interval) * time.Second)
+	}
+}
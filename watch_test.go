@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigWatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	content := "watch:\n  rules:\n    - name: go\n      keywords: [golang]\n      regex: '\\bGo\\b'\n"
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if err := cfg.validate(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if cfg.Watch.Interval != 300 || len(cfg.Watch.Rules) != 1 || cfg.Watch.Rules[0].re == nil {
+		t.Errorf("Expected the rule with the default interval, got %+v", cfg.Watch)
+	}
+
+	cfg.Watch.Rules = []WatchRule{{Name: "bad", Regex: "("}}
+	if err := cfg.validate(); err == nil || !strings.Contains(err.Error(), `"bad"`) {
+		t.Errorf("Expected a regex error, got %v", err)
+	}
+	cfg.Watch.Rules = []WatchRule{{Name: "empty"}}
+	if err := cfg.validate(); err == nil {
+		t.Error("Expected an error for a rule matching nothing")
+	}
+}
+
+func TestWatchRuleMatches(t *testing.T) {
+	config := WatchConfig{Rules: []WatchRule{
+		{Name: "k8s", Keywords: []string{"Kubernetes"}},
+		{Name: "go", Regex: `\bGo\b`},
+		{Name: "blog", Domains: []string{"example.com"}},
+	}}
+	if err := config.compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		article Article
+		rule    string // Empty if no rule matches
+	}{
+		{Article{Title: "Running kubernetes at home"}, "k8s"},
+		{Article{Title: "Notes", Summary: "Upgrading our KUBERNETES clusters"}, "k8s"},
+		{Article{Title: "Go 1.26 released"}, "go"},
+		{Article{Title: "Going places"}, ""},
+		{Article{Title: "Post", Link: "https://blog.example.com/post", Domain: "blog.example.com"}, "blog"},
+		{Article{Title: "Post", Link: "https://notexample.com/post", Domain: "notexample.com"}, ""},
+	}
+	for _, test := range tests {
+		rule := ""
+		for i := range config.Rules {
+			if config.Rules[i].matches(test.article) {
+				rule = config.Rules[i].Name
+				break
+			}
+		}
+		if rule != test.rule {
+			t.Errorf("%+v matched %q, want %q", test.article, rule, test.rule)
+		}
+	}
+}
+
+func TestWatcherPoll(t *testing.T) {
+	config := WatchConfig{Rules: []WatchRule{{Name: "ai", Keywords: []string{"AI"}}}}
+	if err := config.compile(); err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "watch.json")
+	alerted, err := loadWatchLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	w := &watcher{config: config, log: alerted, fetch: testdataFetch(t, true), out: &out}
+	if err := w.poll(); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(out.String(), "[ai] 26364  AI 코딩 도구") || strings.Count(out.String(), "\n") != 1 {
+		t.Errorf("Expected an alert for topic 26364, got %q", out.String())
+	}
+
+	// Alerted topics are remembered across restarts
+	reloaded, err := loadWatchLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out.Reset()
+	w.log = reloaded
+	if err := w.poll(); err != nil || out.Len() != 0 {
+		t.Errorf("Expected no alert again, got %q, %v", out.String(), err)
+	}
+}
+
+func TestRunWatchCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("The test command needs a POSIX shell")
+	}
+	path := filepath.Join(t.TempDir(), "alert.json")
+	alert := watchAlert{Rule: "go", Article: Article{Title: "Go 1.26", CommentsLink: geekNewsBaseURL + "topic?id=7", Domain: "news.hada.io"}}
+	var out bytes.Buffer
+	if err := runWatchCommand(`cat > '`+path+`'; echo "$GN_TEXT_TOPIC_ID"`, alert, &out, &out); err != nil {
+		t.Fatalf("Unexpected error: %v (%s)", err, out.String())
+	}
+	if out.String() != "7\n" {
+		t.Errorf("Expected the topic ID in the environment, got %q", out.String())
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc WatchAlertDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatalf("Invalid JSON %q: %v", data, err)
+	}
+	if doc.SchemaVersion != schemaVersion || doc.Rule != "go" || doc.Article.ID != "7" || doc.Article.Title != "Go 1.26" {
+		t.Errorf("Unexpected alert document %+v", doc)
+	}
+
+	if err := runWatchCommand("exit 3", alert, &out, &out); err == nil {
+		t.Error("Expected an error from a failing command")
+	}
+}
@@ -1,12 +1,18 @@
 package main
 
 import (
+	"fmt"
 	"io"
 	"net/http"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/PuerkitoBio/goquery"
+	"github.com/gelembjuk/articletext"
 	"github.com/mattn/go-runewidth"
+	"github.com/rivo/tview"
 	"golang.org/x/term"
 	"jaytaylor.com/html2text"
 )
@@ -15,20 +21,52 @@ import (
 func getTerminalWidth() int {
 	width, _, err := term.GetSize(int(os.Stdout.Fd()))
 	if err != nil || width <= 0 {
-		return 120 // default fallback
+		return settings.FallbackWidth
 	}
 	return width
 }
 
+// wrapWidth returns the width text is wrapped at outside of a view: the configured
+// wrap_width, or half the terminal
+func wrapWidth() int {
+	if settings.WrapWidth > 0 {
+		return settings.WrapWidth
+	}
+	return getTerminalWidth() / 2
+}
+
 const geekNewsBaseURL = "https://news.hada.io/"
 
+// fetchWebpage downloads url, retrying failed requests with exponential backoff (1s, 2s, ...)
 func fetchWebpage(url string) (string, error) {
-	res, err := http.Get(url)
+	client := &http.Client{Timeout: time.Duration(settings.RequestTimeout) * time.Second}
+
+	var err error
+	for attempt := 0; attempt <= settings.RetryCount; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(1<<(attempt-1)) * time.Second)
+		}
+		var body string
+		body, err = fetchOnce(client, url)
+		if err == nil {
+			return body, nil
+		}
+	}
+	return "", err
+}
+
+func fetchOnce(client *http.Client, url string) (string, error) {
+	res, err := client.Get(url)
 	if err != nil {
 		return "", err
 	}
 	defer res.Body.Close()
 
+	// Server errors are worth retrying; other statuses still carry a page to parse
+	if res.StatusCode >= 500 {
+		return "", fmt.Errorf("%s: %s", url, res.Status)
+	}
+
 	body, err := io.ReadAll(res.Body)
 	if err != nil {
 		return "", err
@@ -38,56 +76,218 @@ func fetchWebpage(url string) (string, error) {
 }
 
 func sanitize(input string) string {
-	sanitized, _ := html2text.FromString(input)
+	sanitized, _ := html2text.FromString(markInlineCode(input))
 	return sanitized
 }
 
-// fetchGeekNewsComments fetches and formats comments for a GeekNews topic
-func fetchGeekNewsComments(topicID string) []string {
-	// Fetch the full topic page (not just comments) to get body content
-	topicURL := geekNewsBaseURL + "topic?id=" + topicID
-	html, err := fetchWebpage(topicURL)
+// markInlineCode wraps inline <code> elements in backticks, which html2text would drop,
+// so that code can still be told apart (and styled) in plain text
+func markInlineCode(input string) string {
+	if !strings.Contains(input, "<code") {
+		return input
+	}
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(input))
+	if err != nil {
+		return input
+	}
+	doc.Find("code").Each(func(i int, s *goquery.Selection) {
+		if s.ParentsFiltered("pre").Length() == 0 {
+			s.SetText("`" + s.Text() + "`")
+		}
+	})
+	html, err := doc.Find("body").Html()
 	if err != nil {
-		return []string{"페이지를 불러오는데 실패했습니다: " + err.Error()}
+		return input
 	}
+	return html
+}
 
-	var lines []string
+// TopicPage is a parsed GeekNews topic page: the topic itself and its comments
+type TopicPage struct {
+	ID       string
+	Content  *TopicContent
+	Comments []Comment
+	Report   *ParseReport
 
-	// Parse and display topic content (body)
-	topicContent, err := parseGeekNewsTopicContent(html)
-	if err == nil && topicContent.Body != "" {
-		lines = append(lines, formatTopicContent(topicContent)...)
-		lines = append(lines, "")
-		// Create separator line matching half terminal width
-		separatorWidth := getTerminalWidth() / 2
-		lines = append(lines, strings.Repeat("─", separatorWidth))
-		lines = append(lines, "")
+	expanded map[int]bool    // Folded replies the reader expanded, by index of the first folded comment
+	unseen   map[string]bool // IDs of the comments new since the last visit
+}
+
+// fetchTopicPage fetches and parses the full topic page (body and comments)
+func fetchTopicPage(topicID string) (*TopicPage, error) {
+	html, err := fetchCached(geekNewsBaseURL + "topic?id=" + topicID)
+	if err != nil {
+		return nil, err
 	}
+	return parseTopicPage(topicID, html)
+}
 
-	// Parse comments
-	comments, err := parseGeekNewsComments(html)
+// parseTopicPage parses the topic content and comments of a topic page
+func parseTopicPage(topicID string, html string) (*TopicPage, error) {
+	page := &TopicPage{ID: topicID, Report: newParseReport()}
+
+	content, topicReport, err := parseGeekNewsTopicContent(html)
 	if err != nil {
-		lines = append(lines, "댓글을 파싱하는데 실패했습니다: "+err.Error())
-		return lines
+		return nil, err
 	}
+	page.Content = content
+	page.Report.Merge(topicReport)
 
-	if len(comments) == 0 {
-		lines = append(lines, "아직 댓글이 없습니다. 오른쪽 화살표 또는 'l' 키를 눌러 기사를 읽어보세요.")
-		return lines
+	comments, commentsReport, err := parseGeekNewsComments(html)
+	if err != nil {
+		return nil, err
 	}
+	page.Comments = comments
+	page.Report.Merge(commentsReport)
+
+	return page, nil
+}
 
-	lines = append(lines, formatComments(comments)...)
+// formatTopicPage formats the topic content followed by its comments, wrapped at width
+func formatTopicPage(page *TopicPage, width int) []string {
+	lines, _ := layoutTopicPage(page, width)
 	return lines
 }
 
-// formatTopicContent formats the topic content (title, meta, body) for display
-func formatTopicContent(content *TopicContent) []string {
+// layoutTopicPage formats a topic page like formatTopicPage, also returning the first line
+// of the topic content and of each comment as scroll anchors
+func layoutTopicPage(page *TopicPage, width int) (lines []string, anchors []int) {
+
+	// Warn before the content when the page no longer matches our selectors
+	if warning := layoutWarning(page.Report); warning != "" {
+		lines = append(lines, warning, "")
+	}
+
+	// Display topic content (body)
+	if page.Content != nil && page.Content.Body != "" {
+		anchors = append(anchors, len(lines))
+		lines = append(lines, formatTopicContent(page.Content, width)...)
+		lines = append(lines, "")
+		// Create separator line matching the text width
+		lines = append(lines, strings.Repeat("─", width))
+		lines = append(lines, "")
+	}
+
+	if len(page.Comments) == 0 {
+		lines = append(lines, "아직 댓글이 없습니다. 오른쪽 화살표 또는 'l' 키를 눌러 기사를 읽어보세요.")
+		return lines, anchors
+	}
+
+	if n := len(page.unseen); n > 0 {
+		lines = append(lines, paint(theme.New, fmt.Sprintf("새 댓글 %d개", n)), "")
+	}
+
+	op := ""
+	if page.Content != nil {
+		op = page.Content.Author
+	}
+	for _, block := range page.blocks() {
+		anchors = append(anchors, len(lines))
+		if block.folded {
+			// Replies deeper than the depth limit are folded into one line
+			indent := commentIndent(settings.CommentDepthLimit+1, width)
+			fold := paint(theme.Meta, fmt.Sprintf("%s 답글 %d개 더 보기", foldMarker, block.end-block.start))
+			if n := page.unseenIn(block); n > 0 {
+				fold += " " + paint(theme.New, fmt.Sprintf("새 댓글 %d개", n))
+			}
+			lines = append(lines, indent+fold, "  ")
+			continue
+		}
+		comment := formatComment(page.Comments[block.start], op, width)
+		if page.unseenIn(block) > 0 {
+			comment[0] += " " + paint(theme.New, newBadge)
+		}
+		lines = append(lines, comment...)
+	}
+	return lines, anchors
+}
+
+// newBadge marks comments posted since the last visit
+const newBadge = "새 댓글"
+
+// commentBlock is a comment or a run of folded replies, comments[start:end], laid out as
+// one block of a topic page
+type commentBlock struct {
+	start, end int
+	folded     bool
+}
+
+// blocks returns the comment blocks of the page in order
+func (page *TopicPage) blocks() []commentBlock {
+	var blocks []commentBlock
+	for i := 0; i < len(page.Comments); i++ {
+		if end := foldEnd(page.Comments, i); end > i && !page.expanded[i] {
+			blocks = append(blocks, commentBlock{start: i, end: end, folded: true})
+			i = end - 1
+			continue
+		}
+		blocks = append(blocks, commentBlock{start: i, end: i + 1})
+	}
+	return blocks
+}
+
+// unseenIn returns the number of new comments in block
+func (page *TopicPage) unseenIn(block commentBlock) int {
+	n := 0
+	for _, comment := range page.Comments[block.start:block.end] {
+		if page.unseen[comment.ID] {
+			n++
+		}
+	}
+	return n
+}
+
+// UserPage is everything shown in the user view: profile, submitted topics and recent comments
+type UserPage struct {
+	Profile  *UserProfile
+	Topics   []Article
+	Comments []UserComment
+	Report   *ParseReport
+}
+
+// fetchUserPage fetches a user's profile, submitted topics and recent comments
+func fetchUserPage(userID string) (*UserPage, error) {
+	query := "?id=" + url.QueryEscape(userID)
+	page := &UserPage{Report: newParseReport()}
+
+	html, err := fetchCached(geekNewsBaseURL + "user" + query)
+	if err != nil {
+		return nil, err
+	}
+	profile, report, err := parseGeekNewsUserProfile(html)
+	if err != nil {
+		return nil, err
+	}
+	if profile.ID == "" {
+		profile.ID = userID
+	}
+	page.Profile = profile
+	page.Report.Merge(report)
+
+	// Topics and comments are optional; the profile is still useful without them
+	if html, err := fetchCached(geekNewsBaseURL + "user_topics" + query); err == nil {
+		if topics, report, err := parseGeekNewsTopicList(html); err == nil {
+			page.Topics = topics
+			page.Report.Merge(report)
+		}
+	}
+	if html, err := fetchCached(geekNewsBaseURL + "user_comments" + query); err == nil {
+		if comments, report, err := parseGeekNewsUserComments(html); err == nil {
+			page.Comments = comments
+			page.Report.Merge(report)
+		}
+	}
+
+	return page, nil
+}
+
+// formatTopicContent formats the topic content (title, meta, body) for display, wrapped at maxWidth
+func formatTopicContent(content *TopicContent, maxWidth int) []string {
 	var lines []string
-	maxWidth := getTerminalWidth() / 2
 
 	// Title
 	if content.Title != "" {
-		lines = append(lines, "[yellow]"+content.Title+"[-]")
+		lines = append(lines, paint(theme.Title, tview.Escape(content.Title)))
 		lines = append(lines, "")
 	}
 
@@ -103,7 +303,7 @@ func formatTopicContent(content *TopicContent) []string {
 		meta = append(meta, content.Points+"P")
 	}
 	if len(meta) > 0 {
-		lines = append(lines, "[gray]"+strings.Join(meta, " · ")+"[-]")
+		lines = append(lines, paint(theme.Meta, tview.Escape(strings.Join(meta, " · "))))
 		lines = append(lines, "")
 	}
 
@@ -123,8 +323,9 @@ func formatTopicContent(content *TopicContent) []string {
 				if subLine == "" {
 					continue
 				}
-				wrappedLines := wrapTextWithRuneWidth(subLine, maxWidth, "")
-				lines = append(lines, wrappedLines...)
+				for _, line := range wrapTextWithRuneWidth(subLine, maxWidth, "") {
+					lines = append(lines, styleBodyLine("", line))
+				}
 			}
 			lines = append(lines, "")
 		}
@@ -133,56 +334,127 @@ func formatTopicContent(content *TopicContent) []string {
 	return lines
 }
 
-// formatComments formats a list of comments for display
-func formatComments(comments []Comment) []string {
-	var lines []string
-	maxWidth := getTerminalWidth() / 2
+// foldMarker starts the line standing in for folded replies
+const foldMarker = "[+]"
+
+// foldEnd returns the end of the run of folded replies starting at comments[i], or i if
+// comments[i] is not folded: replies deeper than comment_depth_limit are folded.
+func foldEnd(comments []Comment, i int) int {
+	if i > 0 && comments[i-1].Depth > settings.CommentDepthLimit {
+		return i // Not the start of a run
+	}
+	end := i
+	for end < len(comments) && comments[end].Depth > settings.CommentDepthLimit {
+		end++
+	}
+	return end
+}
+
+// foldedRuns returns the index of the first comment of each run of folded replies that is
+// not expanded, in page order
+func (page *TopicPage) foldedRuns() []int {
+	var runs []int
+	for i := 0; i < len(page.Comments); i++ {
+		if end := foldEnd(page.Comments, i); end > i {
+			if !page.expanded[i] {
+				runs = append(runs, i)
+			}
+			i = end - 1
+		}
+	}
+	return runs
+}
+
+// expandFold shows the folded replies starting at comment index start
+func (page *TopicPage) expandFold(start int) {
+	if page.expanded == nil {
+		page.expanded = make(map[int]bool)
+	}
+	page.expanded[start] = true
+}
 
+// formatComments formats a list of comments for display, wrapped at maxWidth and marking
+// those by the topic author op
+func formatComments(comments []Comment, op string, maxWidth int) []string {
+	var lines []string
 	for _, comment := range comments {
-		// Create indentation based on depth (limit visual depth to 4 for readability)
-		visualDepth := min(comment.Depth, 4)
-		indent := strings.Repeat("   ", visualDepth*2) + "| "
-
-		// Add author line with time
-		authorLine := indent + comment.Author
-		if comment.Time != "" {
-			authorLine += " (" + comment.Time + ")"
-		}
-		authorLine += " 님:"
-		lines = append(lines, authorLine)
-
-		// Process comment body
-		if comment.Body == "" {
-			lines = append(lines, indent+"[삭제됨]")
-		} else {
-			// Split into paragraphs and wrap each
-			paragraphs := strings.Split(comment.Body, "\n\n")
-			for _, paragraph := range paragraphs {
-				paragraph = strings.TrimSpace(paragraph)
-				if paragraph == "" {
-					continue
-				}
-				wrappedLines := wrapTextWithRuneWidth(paragraph, maxWidth, indent)
-				lines = append(lines, wrappedLines...)
-				lines = append(lines, indent)
+		lines = append(lines, formatComment(comment, op, maxWidth)...)
+	}
+	return lines
+}
+
+// commentIndent returns the indentation for a comment at depth. Each level indents by a tenth
+// of width (2 to 6 columns), and nesting stops deepening at 4 levels or a third of width.
+func commentIndent(depth, width int) string {
+	step := max(2, min(6, width/10))
+	visualDepth := min(depth, 4, width/3/step)
+	return strings.Repeat(" ", visualDepth*step) + "| "
+}
+
+// formatComment formats a single comment for display, wrapped at maxWidth
+func formatComment(comment Comment, op string, maxWidth int) []string {
+	var lines []string
+	indent := commentIndent(comment.Depth, maxWidth)
+
+	// Add author line with time
+	authorStyle := theme.Author
+	if op != "" && comment.Author == op {
+		authorStyle = theme.OP
+	}
+	authorLine := indent + paint(authorStyle, tview.Escape(comment.Author))
+	if comment.Time != "" {
+		authorLine += paint(theme.Meta, " ("+tview.Escape(comment.Time)+")")
+	}
+	authorLine += " 님:"
+	lines = append(lines, authorLine)
+
+	// Process comment body
+	if comment.Body == "" {
+		lines = append(lines, indent+"[삭제됨]")
+	} else {
+		// Split into paragraphs and wrap each
+		paragraphs := strings.Split(comment.Body, "\n\n")
+		for _, paragraph := range paragraphs {
+			paragraph = strings.TrimSpace(paragraph)
+			if paragraph == "" {
+				continue
 			}
-			// Remove trailing empty indent line
-			if len(lines) > 0 && lines[len(lines)-1] == indent {
-				lines = lines[:len(lines)-1]
+			for _, line := range wrapTextWithRuneWidth(paragraph, maxWidth, indent) {
+				lines = append(lines, styleBodyLine(indent, line))
 			}
+			lines = append(lines, indent)
 		}
-
-		// Add depth indicator for deeply nested comments
-		if comment.Depth > 10 {
-			lines = append(lines, indent+"[...]")
+		// Remove trailing empty indent line
+		if len(lines) > 0 && lines[len(lines)-1] == indent {
+			lines = lines[:len(lines)-1]
 		}
+	}
 
-		lines = append(lines, "  ")
+	// Add depth indicator for deeply nested comments
+	if comment.Depth > settings.CommentDepthLimit {
+		lines = append(lines, indent+"[...]")
 	}
 
+	lines = append(lines, "  ")
 	return lines
 }
 
+// layoutText wraps plain text (an extracted article) at width, line by line, returning the
+// first wrapped line of each source line as scroll anchors
+func layoutText(text string, width int) (lines []string, anchors []int) {
+	for _, line := range strings.Split(text, "\n") {
+		anchors = append(anchors, len(lines))
+		if strings.TrimSpace(line) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		for _, wrapped := range wrapTextWithRuneWidth(line, width, "") {
+			lines = append(lines, styleBodyLine("", wrapped))
+		}
+	}
+	return lines, anchors
+}
+
 // wrapTextWithRuneWidth wraps text using runewidth for accurate CJK character width
 func wrapTextWithRuneWidth(text string, maxWidth int, indent string) []string {
 	words := strings.Fields(text)
@@ -191,7 +463,7 @@ func wrapTextWithRuneWidth(text string, maxWidth int, indent string) []string {
 	indentWidth := runewidth.StringWidth(indent)
 	effectiveWidth := maxWidth - indentWidth
 
-	for _, word := range words {
+	for _, word := range splitLongWords(words, effectiveWidth) {
 		wordWidth := runewidth.StringWidth(word)
 		currentWidth := runewidth.StringWidth(currentLine.String())
 
@@ -214,17 +486,52 @@ func wrapTextWithRuneWidth(text string, maxWidth int, indent string) []string {
 	return lines
 }
 
-// fetchExternalLink fetches a topic page and extracts the external article link
-func fetchExternalLink(topicURL string) (string, error) {
-	html, err := fetchWebpage(topicURL)
+// splitLongWords breaks words wider than width (long URLs) into pieces that fit
+func splitLongWords(words []string, width int) []string {
+	if width < 1 {
+		return words
+	}
+	var result []string
+	for _, word := range words {
+		for runewidth.StringWidth(word) > width {
+			piece := runewidth.Truncate(word, width, "")
+			if piece == "" {
+				break // A single character wider than width
+			}
+			result = append(result, piece)
+			word = word[len(piece):]
+		}
+		result = append(result, word)
+	}
+	return result
+}
+
+// extractArticleText fetches the page at url and extracts its article text. The page is
+// fetched here because articletext exits the program when it fails to fetch a page itself.
+func extractArticleText(url string) (string, error) {
+	html, err := fetchCached(url)
 	if err != nil {
 		return "", err
 	}
+	return parseArticleText(html)
+}
+
+// parseArticleText extracts the article text of a page
+func parseArticleText(html string) (string, error) {
+	return articletext.GetArticleText(strings.NewReader(html))
+}
+
+// fetchExternalLink fetches a topic page and extracts the external article link
+func fetchExternalLink(topicURL string) (string, *ParseReport, error) {
+	html, err := fetchCached(topicURL)
+	if err != nil {
+		return "", nil, err
+	}
 
-	link, _, err := parseGeekNewsTopicLink(html)
+	link, _, report, err := parseGeekNewsTopicLink(html)
 	if err != nil {
-		return "", err
+		return "", nil, err
 	}
 
-	return link, nil
+	return link, report, nil
 }
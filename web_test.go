@@ -3,6 +3,8 @@ package main
 import (
 	"strings"
 	"testing"
+
+	"github.com/mattn/go-runewidth"
 )
 
 func TestSanitize(t *testing.T) {
@@ -115,7 +117,7 @@ func TestFormatComments(t *testing.T) {
 		},
 	}
 
-	lines := formatComments(comments)
+	lines := formatComments(comments, "", 60)
 
 	if len(lines) == 0 {
 		t.Error("Expected formatted lines")
@@ -149,7 +151,7 @@ func TestFormatComments(t *testing.T) {
 }
 
 func TestFormatCommentsEmpty(t *testing.T) {
-	lines := formatComments([]Comment{})
+	lines := formatComments([]Comment{}, "", 60)
 
 	if len(lines) != 0 {
 		t.Errorf("Expected empty result for empty comments, got %v", lines)
@@ -166,7 +168,7 @@ func TestFormatTopicContent(t *testing.T) {
 		Points:       "42",
 	}
 
-	lines := formatTopicContent(content)
+	lines := formatTopicContent(content, 60)
 
 	if len(lines) == 0 {
 		t.Error("Expected formatted lines")
@@ -212,7 +214,7 @@ func TestFormatTopicContent(t *testing.T) {
 func TestFormatTopicContentEmpty(t *testing.T) {
 	content := &TopicContent{}
 
-	lines := formatTopicContent(content)
+	lines := formatTopicContent(content, 60)
 
 	// Empty content should produce no lines
 	if len(lines) != 0 {
@@ -231,7 +233,7 @@ func TestFormatCommentsDeletedComment(t *testing.T) {
 		},
 	}
 
-	lines := formatComments(comments)
+	lines := formatComments(comments, "", 60)
 
 	found := false
 	for _, line := range lines {
@@ -244,3 +246,52 @@ func TestFormatCommentsDeletedComment(t *testing.T) {
 		t.Error("Expected deleted comment indicator '[삭제됨]'")
 	}
 }
+
+func TestLayoutTopicPageAnchors(t *testing.T) {
+	page := &TopicPage{
+		Content: &TopicContent{Title: "제목", Author: "op", Body: "본문입니다."},
+		Comments: []Comment{
+			{Author: "user1", Body: "첫 번째 댓글입니다. 조금 길게 써서 좁은 화면에서는 여러 줄로 나뉘도록 합니다."},
+			{Author: "op", Body: "답글입니다.", Depth: 1},
+		},
+	}
+
+	for _, width := range []int{30, 80} {
+		lines, anchors := layoutTopicPage(page, width)
+		if len(anchors) != 3 {
+			t.Fatalf("width %d: expected an anchor for the topic and each comment, got %v", width, anchors)
+		}
+		if !strings.Contains(lines[anchors[1]], "user1") || !strings.Contains(lines[anchors[2]], "op") {
+			t.Errorf("width %d: expected anchors on comment author lines, got %q and %q", width, lines[anchors[1]], lines[anchors[2]])
+		}
+		for _, line := range lines {
+			if w := runewidth.StringWidth(styleTagRegex.ReplaceAllString(line, "")); w > width {
+				t.Errorf("width %d: line %q is %d wide", width, line, w)
+			}
+		}
+	}
+}
+
+func TestCommentIndentScalesWithWidth(t *testing.T) {
+	if narrow, wide := commentIndent(1, 30), commentIndent(1, 100); len(narrow) >= len(wide) {
+		t.Errorf("Expected narrower indent on a narrow view, got %q and %q", narrow, wide)
+	}
+	if indent := commentIndent(10, 40); runewidth.StringWidth(indent) > 40/3+2 {
+		t.Errorf("Expected indent capped at a third of the width, got %d columns", len(indent))
+	}
+	if commentIndent(0, 80) != "| " {
+		t.Errorf("Expected no indentation for top-level comments, got %q", commentIndent(0, 80))
+	}
+}
+
+func TestWrapTextWithRuneWidthLongWord(t *testing.T) {
+	lines := wrapTextWithRuneWidth("see https://example.com/a/very/long/path/that/does/not/fit", 20, "")
+	for _, line := range lines {
+		if runewidth.StringWidth(line) > 20 {
+			t.Errorf("Expected long word to be broken at 20 columns, got %q", line)
+		}
+	}
+	if joined := strings.Join(lines, ""); !strings.Contains(joined, "https://example.com/a/very/long/path/that/does/not/fit") {
+		t.Errorf("Expected the word to be kept whole across lines, got %v", lines)
+	}
+}